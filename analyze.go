@@ -0,0 +1,522 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	marshalerType       = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*interface{ MarshalText() ([]byte, error) })(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*interface{ UnmarshalText([]byte) error })(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// typedValue is a decoded JSON value paired with the Go type it is decoded into. For object keys
+// decoded into a struct, owner is the struct type, object is the JSON object holding the key, and
+// field is the destination field, which is nil when the struct has no field for the key.
+type typedValue struct {
+	location string
+	key      string
+	value    interface{}
+	typ      reflect.Type
+	owner    reflect.Type
+	object   map[string]interface{}
+	field    *structField
+}
+
+// walkTyped calls fn for the decoded JSON value and each value nested in it, alongside the type
+// encoding/json decodes it into. Values without a destination type are visited but not descended
+// into, as are values decoded by a json.Unmarshaler or encoding.TextUnmarshaler.
+func walkTyped(tv typedValue, fn func(typedValue)) {
+	fn(tv)
+	if tv.typ == nil {
+		return
+	}
+	t := tv.typ
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if implementsUnmarshaler(t) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		jsonMap, ok := tv.value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := cachedTypeFields(t)
+		for _, key := range keys(jsonMap) {
+			child := typedValue{location: getLocation(tv.location, key), key: key, value: jsonMap[key], owner: t, object: jsonMap}
+			if field, ok := findField(fields, key); ok {
+				child.field, child.typ = &field, field.typ
+			}
+			walkTyped(child, fn)
+		}
+	case reflect.Map:
+		jsonMap, ok := tv.value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, key := range keys(jsonMap) {
+			walkTyped(typedValue{location: getLocation(tv.location, key), key: key, value: jsonMap[key], typ: t.Elem()}, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		jsonSlice, ok := tv.value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, elem := range jsonSlice {
+			walkTyped(typedValue{location: fmt.Sprintf("%s[%d]", tv.location, i), value: elem, typ: t.Elem()}, fn)
+		}
+	}
+}
+
+// getJSONNumberValue decodes the first JSON value in text, keeping numbers as json.Number so
+// their original text is available. Unlike getJSONValue, it ignores any data after the value,
+// the same way the json.Decoder used by StructCheck does.
+func getJSONNumberValue(text []byte) (interface{}, error) {
+	var value interface{}
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	return value, d.Decode(&value)
+}
+
+// unmatchedKey is a JSON key that has no destination field in the struct it is decoded into.
+type unmatchedKey struct {
+	location string
+	key      string
+	value    interface{}
+	owner    reflect.Type
+}
+
+// findUnmatchedKeys walks the decoded JSON value alongside the type it is decoded into and
+// returns every object key that encoding/json would silently discard.
+func findUnmatchedKeys(location string, t reflect.Type, value interface{}) []unmatchedKey {
+	var unmatched []unmatchedKey
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		if tv.owner != nil && tv.field == nil {
+			unmatched = append(unmatched, unmatchedKey{location: tv.location, key: tv.key, value: tv.value, owner: tv.owner})
+		}
+	})
+	return unmatched
+}
+
+// findUncapturedKeys returns the JSON keys that findUnmatchedKeys returns, except the ones that
+// findTagNearMisses and findDroppedKeys explain.
+func findUncapturedKeys(location string, t reflect.Type, value interface{}) []unmatchedKey {
+	explained := map[string]bool{}
+	for _, nearMiss := range findTagNearMisses(location, t, value) {
+		explained[nearMiss.location] = true
+	}
+	for _, dropped := range findDroppedKeys(location, t, value) {
+		explained[dropped.location] = true
+	}
+	var uncaptured []unmatchedKey
+	for _, u := range findUnmatchedKeys(location, t, value) {
+		if !explained[u.location] {
+			uncaptured = append(uncaptured, u)
+		}
+	}
+	return uncaptured
+}
+
+// tagNearMiss is a JSON key that almost, but not exactly, matches the name of a struct field.
+type tagNearMiss struct {
+	location string
+	key      string
+	owner    reflect.Type
+	field    structField
+}
+
+func (n tagNearMiss) Error() string {
+	return fmt.Sprintf("%s: tag `%s` on %s.%s vs key `%s`", n.location, n.field.name, n.owner, n.field.goName, n.key)
+}
+
+// findTagNearMisses returns the JSON keys that are decoded into a field whose name only matches
+// case-insensitively, and the keys with no destination field that are a likely misspelling of a
+// field name. Both survive decoding silently but fail the round trip as a pair of confusing
+// nil mismatches.
+func findTagNearMisses(location string, t reflect.Type, value interface{}) []tagNearMiss {
+	var nearMisses []tagNearMiss
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		if tv.owner == nil {
+			return
+		}
+		if tv.field != nil {
+			if tv.field.name != tv.key {
+				nearMisses = append(nearMisses, tagNearMiss{location: tv.location, key: tv.key, owner: tv.owner, field: *tv.field})
+			}
+			return
+		}
+		for _, field := range cachedTypeFields(tv.owner) {
+			if _, ok := tv.object[field.name]; !ok && isNearMiss(field.name, tv.key) {
+				nearMisses = append(nearMisses, tagNearMiss{location: tv.location, key: tv.key, owner: tv.owner, field: field})
+				return
+			}
+		}
+	})
+	return nearMisses
+}
+
+// droppedKey is a JSON key whose data is dropped because the struct field it would otherwise
+// be decoded into is tagged `json:"-"` or is unexported.
+type droppedKey struct {
+	location string
+	owner    reflect.Type
+	field    reflect.StructField
+}
+
+func (d droppedKey) Error() string {
+	if !d.field.IsExported() {
+		return fmt.Sprintf("data at %s is ignored because field %s.%s is unexported", d.location, d.owner, d.field.Name)
+	}
+	return fmt.Sprintf("data at %s is intentionally dropped by field %s.%s, which is tagged `json:\"-\"`", d.location, d.owner, d.field.Name)
+}
+
+// findDroppedKeys returns the JSON keys holding data that have no destination field because the
+// field whose Go name matches the key case-insensitively is tagged `json:"-"` or is unexported.
+func findDroppedKeys(location string, t reflect.Type, value interface{}) []droppedKey {
+	var dropped []droppedKey
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		if tv.owner == nil || tv.field != nil || isEmpty(tv.value) {
+			return
+		}
+		if field, ok := droppingField(tv.owner, tv.key); ok {
+			dropped = append(dropped, droppedKey{location: tv.location, owner: tv.owner, field: field})
+		}
+	})
+	return dropped
+}
+
+func droppingField(t reflect.Type, key string) (reflect.StructField, bool) {
+	field, ok := t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, key) })
+	return field, ok && (field.Tag.Get("json") == "-" || !field.IsExported())
+}
+
+// findAmbiguousNulls returns an error for each field that receives an explicit null from the
+// JSON but can't hold one, so the null decodes to the field's zero value and can't be told apart
+// from it. Each field is reported once, at the first location where it receives a null.
+func findAmbiguousNulls(location string, t reflect.Type, value interface{}) []error {
+	var errors []error
+	reported := map[string]bool{}
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		if tv.field == nil || tv.value != nil || isNillable(tv.typ) {
+			return
+		}
+		name := fmt.Sprintf("%s.%s", tv.owner, tv.field.goName)
+		if reported[name] {
+			return
+		}
+		reported[name] = true
+		errors = append(errors, fmt.Errorf("%s: explicit null is decoded into %s as its zero value %s; use *%s if null is meaningful", tv.location, describeDestination(tv), zeroValueString(tv.typ), tv.typ))
+	})
+	return errors
+}
+
+func isNillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func zeroValueString(t reflect.Type) string {
+	zero := reflect.Zero(t).Interface()
+	if text, err := json.Marshal(zero); err == nil {
+		return string(text)
+	}
+	return fmt.Sprintf("%v", zero)
+}
+
+// isNearMiss reports whether a field name and a JSON key look like the same identifier spelled
+// differently, either by separators ("member_id" vs "memberId") or by a small typo.
+func isNearMiss(name, key string) bool {
+	normalizedName, normalizedKey := normalizeIdentifier(name), normalizeIdentifier(key)
+	if normalizedName == normalizedKey {
+		return true
+	}
+	maxDistance := 1
+	if len(normalizedKey) > 6 {
+		maxDistance = 2
+	}
+	return len(normalizedKey) > 3 && levenshtein(normalizedName, normalizedKey) <= maxDistance
+}
+
+func normalizeIdentifier(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// findLossyNumbers returns an error for each JSON number that can't be decoded into its
+// destination type exactly: fractional or out of range numbers decoded into integer types, and
+// integers too large to be represented exactly by floating point types (including interface{},
+// which encoding/json decodes numbers into as float64). The second kind round trips without a
+// mismatch, since both sides of the comparison lose the same precision.
+func findLossyNumbers(location string, t reflect.Type, value interface{}) []error {
+	var errors []error
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		number, ok := tv.value.(json.Number)
+		if !ok || tv.typ == nil {
+			return
+		}
+		typ := tv.typ
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if implementsUnmarshaler(typ) {
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if _, err := strconv.ParseInt(string(number), 10, typ.Bits()); err != nil {
+				errors = append(errors, fmt.Errorf("%s: %s can't be decoded into %s without losing precision; consider float64 or json.Number", tv.location, number, describeDestination(tv)))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if _, err := strconv.ParseUint(string(number), 10, typ.Bits()); err != nil {
+				errors = append(errors, fmt.Errorf("%s: %s can't be decoded into %s without losing precision; consider float64 or json.Number", tv.location, number, describeDestination(tv)))
+			}
+		case reflect.Float32, reflect.Float64, reflect.Interface:
+			bits := 64
+			if typ.Kind() == reflect.Float32 {
+				bits = 32
+			}
+			if decoded, lossy := isLossyFloat(string(number), bits); lossy {
+				errors = append(errors, fmt.Errorf("%s: %s loses precision when decoded into %s (decodes as %s); consider int64 or json.Number", tv.location, number, describeDestination(tv), decoded))
+			}
+		}
+	})
+	return errors
+}
+
+// isLossyFloat reports whether the JSON number literal changes value when decoded into a
+// floating point number of the given bit size and encoded again, and returns the re-encoded
+// value. Integers must be represented exactly, since comparing them as float64 hides the loss.
+func isLossyFloat(literal string, bits int) (string, bool) {
+	f, err := strconv.ParseFloat(literal, bits)
+	if err != nil {
+		return literal, true
+	}
+	if !strings.ContainsAny(literal, ".eE") {
+		exact, ok := new(big.Int).SetString(literal, 10)
+		actual, _ := big.NewFloat(f).Int(nil)
+		return strconv.FormatFloat(f, 'f', -1, bits), ok && exact.Cmp(actual) != 0
+	}
+	decoded := strconv.FormatFloat(f, 'g', -1, bits)
+	f64, _ := strconv.ParseFloat(literal, 64)
+	roundTrip, _ := strconv.ParseFloat(decoded, 64)
+	return decoded, roundTrip != f64
+}
+
+// timeLayouts are the layouts tried when identifying how a fixture formats a timestamp.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"20060102",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+}
+
+// findTimeFormatIssues returns an error for each string decoded into a time.Time that either
+// can't be decoded, because it isn't in RFC 3339 format, or that time.Time encodes differently,
+// such as "+00:00" becoming "Z". The error names the layout the fixture uses when it can be
+// identified, so a custom type using that layout can be written.
+func findTimeFormatIssues(location string, t reflect.Type, value interface{}) []error {
+	var errors []error
+	walkTyped(typedValue{location: location, value: value, typ: t}, func(tv typedValue) {
+		s, ok := tv.value.(string)
+		if !ok || tv.typ == nil {
+			return
+		}
+		typ := tv.typ
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ != timeType {
+			return
+		}
+		const suggestion = "consider a type wrapping time.Time with MarshalJSON and UnmarshalJSON methods using that layout"
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			layout := detectTimeLayout(s)
+			if layout == "" {
+				errors = append(errors, fmt.Errorf("%s: %s can't decode %q, which is in an unrecognized format", tv.location, describeDestination(tv), s))
+				return
+			}
+			errors = append(errors, fmt.Errorf("%s: %s can't decode %q, which uses layout %q; %s", tv.location, describeDestination(tv), s, layout, suggestion))
+			return
+		}
+		if encoded := parsed.Format(time.RFC3339Nano); encoded != s {
+			errors = append(errors, fmt.Errorf("%s: %s re-encodes %q as %q; consider a type wrapping time.Time with MarshalJSON and UnmarshalJSON methods that preserve the fixture's format", tv.location, describeDestination(tv), s, encoded))
+		}
+	})
+	return errors
+}
+
+func detectTimeLayout(s string) string {
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return layout
+		}
+	}
+	return ""
+}
+
+// describeDestination describes the Go type a value is decoded into, naming the struct field
+// when there is one.
+func describeDestination(tv typedValue) string {
+	if tv.field != nil {
+		return fmt.Sprintf("%s field %s.%s", tv.typ, tv.owner, tv.field.goName)
+	}
+	return tv.typ.String()
+}
+
+func implementsUnmarshaler(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return pt.Implements(unmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// suggestFields returns one error per destination struct listing Go field declarations that
+// would capture the unmatched keys holding data. Keys with empty values are skipped since
+// they don't cause the round trip to fail.
+func suggestFields(unmatched []unmatchedKey) []error {
+	var owners []reflect.Type
+	suggestions := map[reflect.Type][]string{}
+	for _, u := range unmatched {
+		if isEmpty(u.value) {
+			continue
+		}
+		if _, ok := suggestions[u.owner]; !ok {
+			owners = append(owners, u.owner)
+		}
+		suggestions[u.owner] = append(suggestions[u.owner], fmt.Sprintf("\t%s %s `json:\"%s\"`", goFieldName(u.key), goTypeName(u.value), u.key))
+	}
+	var errors []error
+	for _, owner := range owners {
+		errors = append(errors, fmt.Errorf("suggested field additions for %s:\n%s", owner, strings.Join(suggestions[owner], "\n")))
+	}
+	return errors
+}
+
+var commonInitialisms = map[string]bool{"api": true, "http": true, "id": true, "json": true, "url": true, "uuid": true}
+
+// goFieldName converts a JSON key such as "member-id" or "memberId" to an exported Go field
+// name such as "MemberID".
+func goFieldName(key string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for i, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(word) > 0 && !unicode.IsUpper(word[len(word)-1]):
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	var sb strings.Builder
+	for _, w := range words {
+		if commonInitialisms[strings.ToLower(w)] {
+			sb.WriteString(strings.ToUpper(w))
+			continue
+		}
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "Field" + name
+	}
+	return name
+}
+
+// goTypeName infers the Go type that would hold a decoded JSON value.
+func goTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "float64"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "map[string]interface{}"
+	case []interface{}:
+		elemTypes := map[string]bool{}
+		for _, elem := range v {
+			if elem != nil {
+				elemTypes[goTypeName(elem)] = true
+			}
+		}
+		if len(elemTypes) == 1 {
+			for elemType := range elemTypes {
+				return "[]" + elemType
+			}
+		}
+		return "[]interface{}"
+	}
+	return "interface{}"
+}
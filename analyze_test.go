@@ -0,0 +1,232 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type embeddedStruct struct {
+	Inner string `json:"inner"`
+}
+
+type outerStruct struct {
+	embeddedStruct
+	Name  string               `json:"name"`
+	Items []subStruct          `json:"items"`
+	Extra map[string]subStruct `json:"extra"`
+}
+
+func TestFindUnmatchedKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{"all matched", `{"inner": "x", "name": "y", "items": [{"a": "1"}], "extra": {"k": {"b": "2"}}}`, nil},
+		{"case-insensitive match", `{"NAME": "y"}`, nil},
+		{"top level", `{"name": "y", "memberId": 1}`, []string{"memberId"}},
+		{"in slice", `{"items": [{"a": "1"}, {"c": "3"}]}`, []string{"items[1].c"}},
+		{"in map", `{"extra": {"k": {"d": "4"}}}`, []string{"extra.k.d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := unmarshalNumbers([]byte(tt.json), &value); err != nil {
+				t.Fatal(err)
+			}
+			var locations []string
+			for _, u := range findUnmatchedKeys("", reflect.TypeOf(&outerStruct{}), value) {
+				locations = append(locations, u.location)
+			}
+			if !reflect.DeepEqual(tt.expected, locations) {
+				t.Errorf("unmatched keys mismatch. want: %v, got: %v", tt.expected, locations)
+			}
+		})
+	}
+}
+
+func TestFindTagNearMisses(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{"exact", `{"memberID": "1", "firstName": "a"}`, nil},
+		{"case", `{"memberId": "1"}`, []string{"memberId: tag `memberID` on jsonassert.nearMissStruct.MemberID vs key `memberId`"}},
+		{"separator", `{"first_name": "a"}`, []string{"first_name: tag `firstName` on jsonassert.nearMissStruct.FirstName vs key `first_name`"}},
+		{"typo", `{"fristName": "a"}`, []string{"fristName: tag `firstName` on jsonassert.nearMissStruct.FirstName vs key `fristName`"}},
+		{"field already matched", `{"firstName": "a", "firstNames": "b"}`, nil},
+		{"unrelated", `{"lastName": "a"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := unmarshalNumbers([]byte(tt.json), &value); err != nil {
+				t.Fatal(err)
+			}
+			var messages []string
+			for _, nearMiss := range findTagNearMisses("", reflect.TypeOf(&nearMissStruct{}), value) {
+				messages = append(messages, nearMiss.Error())
+			}
+			if !reflect.DeepEqual(tt.expected, messages) {
+				t.Errorf("near misses mismatch. want: %v, got: %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestGoFieldName(t *testing.T) {
+	tests := map[string]string{
+		"memberId":   "MemberID",
+		"member-id":  "MemberID",
+		"first_name": "FirstName",
+		"URL":        "URL",
+		"2fa":        "Field2fa",
+	}
+	for key, expected := range tests {
+		if name := goFieldName(key); name != expected {
+			t.Errorf("goFieldName(%q) = %q, want %q", key, name, expected)
+		}
+	}
+}
+
+func TestGoTypeName(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected string
+	}{
+		{`"a"`, "string"},
+		{`1.5`, "float64"},
+		{`true`, "bool"},
+		{`null`, "interface{}"},
+		{`{"a": 1}`, "map[string]interface{}"},
+		{`["a", null, "b"]`, "[]string"},
+		{`["a", 1]`, "[]interface{}"},
+	}
+	for _, tt := range tests {
+		var value interface{}
+		unmarshalNumbers([]byte(tt.json), &value)
+		if name := goTypeName(value); name != tt.expected {
+			t.Errorf("goTypeName(%s) = %q, want %q", tt.json, name, tt.expected)
+		}
+	}
+}
+
+type numbersStruct struct {
+	Count   int                `json:"count"`
+	Small   int8               `json:"small"`
+	ID      float64            `json:"id"`
+	Ratio   float32            `json:"ratio"`
+	Any     interface{}        `json:"any"`
+	Amounts map[string]float64 `json:"amounts"`
+	Exact   json.Number        `json:"exact"`
+}
+
+func TestFindLossyNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{"exact", `{"count": 2, "small": -128, "id": 9007199254740992, "ratio": 0.1, "any": 1.5, "exact": 9007199254740993}`, nil},
+		{"fraction into int", `{"count": 2.5}`, []string{"count: 2.5 can't be decoded into int field jsonassert.numbersStruct.Count without losing precision; consider float64 or json.Number"}},
+		{"out of range int", `{"small": 300}`, []string{"small: 300 can't be decoded into int8 field jsonassert.numbersStruct.Small without losing precision; consider float64 or json.Number"}},
+		{"large integer into float64", `{"id": 9007199254740993}`, []string{"id: 9007199254740993 loses precision when decoded into float64 field jsonassert.numbersStruct.ID (decodes as 9007199254740992); consider int64 or json.Number"}},
+		{"large integer into interface", `{"any": 9007199254740993}`, []string{"any: 9007199254740993 loses precision when decoded into interface {} field jsonassert.numbersStruct.Any (decodes as 9007199254740992); consider int64 or json.Number"}},
+		{"fraction into float32", `{"ratio": 0.123456789}`, []string{"ratio: 0.123456789 loses precision when decoded into float32 field jsonassert.numbersStruct.Ratio (decodes as 0.12345679); consider int64 or json.Number"}},
+		{"map value", `{"amounts": {"a": 12345678901234567890}}`, []string{"amounts.a: 12345678901234567890 loses precision when decoded into float64 (decodes as 12345678901234567000); consider int64 or json.Number"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := getJSONNumberValue([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var messages []string
+			for _, err := range findLossyNumbers("", reflect.TypeOf(&numbersStruct{}), value) {
+				messages = append(messages, err.Error())
+			}
+			if !reflect.DeepEqual(tt.expected, messages) {
+				t.Errorf("lossy numbers mismatch. want: %v, got: %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+type timesStruct struct {
+	Created  time.Time  `json:"created"`
+	Updated  *time.Time `json:"updated"`
+	Birthday string     `json:"birthday"`
+}
+
+func TestFindTimeFormatIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{"rfc3339", `{"created": "2024-06-01T00:00:00Z", "updated": "2024-06-01T10:30:00.5-05:00", "birthday": "06/01/2024"}`, nil},
+		{"date only", `{"created": "2024-06-01"}`, []string{`created: time.Time field jsonassert.timesStruct.Created can't decode "2024-06-01", which uses layout "2006-01-02"; consider a type wrapping time.Time with MarshalJSON and UnmarshalJSON methods using that layout`}},
+		{"us date", `{"updated": "06/01/2024"}`, []string{`updated: *time.Time field jsonassert.timesStruct.Updated can't decode "06/01/2024", which uses layout "01/02/2006"; consider a type wrapping time.Time with MarshalJSON and UnmarshalJSON methods using that layout`}},
+		{"unrecognized", `{"created": "yesterday"}`, []string{`created: time.Time field jsonassert.timesStruct.Created can't decode "yesterday", which is in an unrecognized format`}},
+		{"re-encoded", `{"created": "2024-06-01T00:00:00.000+00:00"}`, []string{`created: time.Time field jsonassert.timesStruct.Created re-encodes "2024-06-01T00:00:00.000+00:00" as "2024-06-01T00:00:00Z"; consider a type wrapping time.Time with MarshalJSON and UnmarshalJSON methods that preserve the fixture's format`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := getJSONNumberValue([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var messages []string
+			for _, err := range findTimeFormatIssues("", reflect.TypeOf(&timesStruct{}), value) {
+				messages = append(messages, err.Error())
+			}
+			if !reflect.DeepEqual(tt.expected, messages) {
+				t.Errorf("time format issues mismatch. want: %v, got: %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+type droppedStruct struct {
+	Name     string `json:"name"`
+	Password string `json:"-"`
+	token    string
+	embeddedDropped
+}
+
+type embeddedDropped struct {
+	Secret string `json:"-"`
+}
+
+func TestFindDroppedKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{"nothing dropped", `{"name": "a"}`, nil},
+		{"empty value", `{"name": "a", "password": ""}`, nil},
+		{"dropped", `{"name": "a", "password": "b"}`, []string{"data at password is intentionally dropped by field jsonassert.droppedStruct.Password, which is tagged `json:\"-\"`"}},
+		{"promoted", `{"Secret": "b"}`, []string{"data at Secret is intentionally dropped by field jsonassert.droppedStruct.Secret, which is tagged `json:\"-\"`"}},
+		{"unrelated", `{"other": "b"}`, nil},
+		{"unexported", `{"Token": "b"}`, []string{"data at Token is ignored because field jsonassert.droppedStruct.token is unexported"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := getJSONNumberValue([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var messages []string
+			for _, dropped := range findDroppedKeys("", reflect.TypeOf(&droppedStruct{}), value) {
+				messages = append(messages, dropped.Error())
+			}
+			if !reflect.DeepEqual(tt.expected, messages) {
+				t.Errorf("dropped keys mismatch. want: %v, got: %v", tt.expected, messages)
+			}
+		})
+	}
+}
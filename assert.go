@@ -5,19 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
+	"math"
+	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 var nilVal = reflect.ValueOf(nil)
 
-type Testing interface {
-	Error(args ...interface{})
-	Errorf(format string, args ...interface{})
-	Helper()
-}
-
 // StructCheck is a convenience function for calling Equal. It is useful for verifying that the
 // struct(s) you've created to receive JSON data in your application can losslessly encode and
 // decode that JSON data. StructCheck will:
@@ -25,35 +25,345 @@ type Testing interface {
 //   2. Encode the text in the JSON file to the result map, struct or slice
 //   3. Decode the result map, struct, or slice back to JSON
 //   4. Compare the input JSON text with the output JSON text using the Equal function
+// Before opening the file, StructCheck checks the result for problems encoding/json would
+// silently ignore or report without naming the field, such as ambiguous fields promoted from
+// embedded structs, maps with unsupported key types, channels, funcs and pointer cycles, and
+// fails early if it finds any. When the comparison fails, StructCheck also reports JSON keys
+// that nearly match a field's json tag or that are dropped by a field tagged "-" or an
+// unexported field. Any other JSON key that has no destination field is reported as present in
+// JSON but having no field, in place of the missing values under it, and Go field declarations
+// are suggested for those keys. Options for a single fixture can be kept next to it in a sidecar
+// rules file, such as testdata/complete.json.rules, in the format read by LoadConfig.
 func StructCheck(t Testing, filename string, result interface{}) {
 	t.Helper()
-	var originalText, encodedText bytes.Buffer
+	NewComparer().StructCheck(t, filename, result)
+}
 
-	isMapType, err := resultArgCheck(result)
+// StructCheck is like the package-level StructCheck, but compares the JSON using the options
+// of the Comparer.
+func (c *Comparer) StructCheck(t Testing, filename string, result interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	isMapType, ok := checkResult(t, result)
+	if !ok {
+		return
+	}
+	sidecar, err := sidecarOptions(filename)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c = c.With(sidecar...)
+	c.file = filename
+
+	originalText, err := c.readFile(filename)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c.roundTrip(t, filename, originalText, result, isMapType, true)
+}
+
+// StructCheckGlob runs StructCheck on every file matching pattern, as filepath.Glob matches it,
+// decoding each one into a new value of the type result points to, so a directory of sample
+// payloads can be checked without a table of filenames:
+//   jsonassert.StructCheckGlob(t, "testdata/claims/*.json", &Claim{})
+// Failures name the file they are in. It's an error for pattern to match no files, so that a
+// mistyped pattern doesn't pass silently.
+func StructCheckGlob(t Testing, pattern string, result interface{}) {
+	t.Helper()
+	NewComparer().StructCheckGlob(t, pattern, result)
+}
+
+// StructCheckGlob is like the package-level StructCheckGlob, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) StructCheckGlob(t Testing, pattern string, result interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	if _, ok := checkResult(t, result); !ok {
+		return
+	}
+	filenames, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Errorf("invalid pattern %s: %v", pattern, err)
+		return
+	}
+	checked := 0
+	for _, filename := range filenames {
+		if info, err := os.Stat(filename); err == nil && info.IsDir() {
+			continue
+		}
+		c.StructCheck(t, filename, reflect.New(reflect.TypeOf(result).Elem()).Interface())
+		checked++
+	}
+	if checked == 0 {
+		t.Errorf("no files match %s", pattern)
+	}
+}
+
+// StructCheckFS is like StructCheck, but reads filename from fsys, such as an embed.FS holding
+// fixtures that ship inside the test binary:
+//   //go:embed testdata
+//   var fixtures embed.FS
+//
+//   jsonassert.StructCheckFS(t, fixtures, "testdata/complete.json", &Claim{})
+// A sidecar rules file is read from fsys too. Since fsys may be read-only, JSONASSERT_UPDATE
+// doesn't rewrite the fixture.
+func StructCheckFS(t Testing, fsys fs.FS, filename string, result interface{}) {
+	t.Helper()
+	NewComparer().StructCheckFS(t, fsys, filename, result)
+}
+
+// StructCheckFS is like the package-level StructCheckFS, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) StructCheckFS(t Testing, fsys fs.FS, filename string, result interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	isMapType, ok := checkResult(t, result)
+	if !ok {
+		return
+	}
+	sidecar, err := sidecarOptionsFS(fsys, filename)
 	if err != nil {
 		t.Error(err)
 		return
 	}
+	c = c.With(sidecar...)
+	c.file = filename
 
-	f, err := os.Open(filename)
+	originalText, err := c.readFileFS(fsys, filename)
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	defer f.Close()
+	c.roundTrip(t, filename, originalText, result, isMapType, false)
+}
+
+// StructCheckBytes is like StructCheck, but checks the JSON in data, such as a payload captured
+// at runtime, instead of reading a file. name identifies the JSON in failures, as the filename
+// does for StructCheck. There is no fixture to rewrite, so JSONASSERT_UPDATE doesn't apply.
+func StructCheckBytes(t Testing, name string, data []byte, result interface{}) {
+	t.Helper()
+	NewComparer().StructCheckBytes(t, name, data, result)
+}
+
+// StructCheckBytes is like the package-level StructCheckBytes, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) StructCheckBytes(t Testing, name string, data []byte, result interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	isMapType, ok := checkResult(t, result)
+	if !ok {
+		return
+	}
+	if err := c.checkText(data); err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	c = c.With()
+	c.file = name
+	c.roundTrip(t, name, data, result, isMapType, false)
+}
+
+// StructCheckReader is like StructCheckBytes, but reads the JSON from r, such as an HTTP body or
+// a message from a queue. It reads no more than the size set by WithMaxSize.
+func StructCheckReader(t Testing, name string, r io.Reader, result interface{}) {
+	t.Helper()
+	NewComparer().StructCheckReader(t, name, r, result)
+}
+
+// StructCheckReader is like the package-level StructCheckReader, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) StructCheckReader(t Testing, name string, r io.Reader, result interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	if c.maxSize > 0 {
+		r = io.LimitReader(r, c.maxSize+1) // StructCheckBytes reports that it exceeds the size
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("error reading %s: %v", name, err)
+		return
+	}
+	c.StructCheckBytes(t, name, data, result)
+}
 
-	r := io.TeeReader(f, &originalText) // save original text to buffer while decoding JSON to result
-	if err := json.NewDecoder(r).Decode(result); err != nil {
-		t.Errorf("error decoding json in %s: %v", filename, err)
+// checkResult checks the result argument of StructCheck, reporting problems to t, and returns
+// whether it is a struct or map rather than a slice.
+func checkResult(t Testing, result interface{}) (isMapType bool, ok bool) {
+	t.Helper()
+	isMapType, err := resultArgCheck(result)
+	if err != nil {
+		t.Error(err)
+		return false, false
+	}
+	if errors := preflightErrors(reflect.ValueOf(result)); len(errors) > 0 {
+		for _, err := range errors {
+			t.Error(err)
+		}
+		return false, false
+	}
+	return isMapType, true
+}
+
+// roundTrip decodes originalText, which name identifies, into result, encodes the result and
+// compares the two, reporting the differences to t. If updatable is set and updating is
+// enabled, it rewrites the fixture name instead.
+func (c *Comparer) roundTrip(t Testing, name string, originalText []byte, result interface{}, isMapType, updatable bool) {
+	t.Helper()
+	if err := c.decode(originalText, result); err != nil {
+		t.Errorf("error decoding json in %s: %v", name, err)
+		c.notifyAnalysis(t, originalText, result, false)
 		return
 	}
 
-	json.NewEncoder(&encodedText).Encode(result)
+	encodedText, err := c.encode(result)
+	if err != nil {
+		t.Errorf("error encoding json for %s: %v", name, err)
+		return
+	}
+	var errors []error
 	if isMapType {
-		notifyErrors(t, filename, EqualMap(originalText.Bytes(), encodedText.Bytes()))
+		errors = c.EqualMap(originalText, encodedText)
 	} else {
-		notifyErrors(t, filename, EqualSlice(originalText.Bytes(), encodedText.Bytes()))
+		errors = c.EqualSlice(originalText, encodedText)
+	}
+	errors = c.explainUncapturedKeys(originalText, result, errors)
+	if updatable && c.update && len(errors) > 0 {
+		if err := c.updateFixture(name, encodedText); err != nil {
+			t.Error(err)
+		}
+		c.notifyAnalysis(t, originalText, result, false)
+		return
+	}
+	c.notifyErrors(t, name, errors)
+	if c.verbose && len(errors) > 0 {
+		var tree strings.Builder
+		if err := c.WriteTree(&tree, originalText, encodedText); err == nil {
+			logf(t, "differences in %s:\n%s", name, tree.String())
+		}
+	}
+	c.notifyDiff(t, originalText, encodedText, errors)
+	c.notifyAnalysis(t, originalText, result, len(errors) > 0)
+}
+
+// updateFixture replaces the contents of filename with the JSON in text, indented as set by
+// WithIndent.
+func (c *Comparer) updateFixture(filename string, text []byte) error {
+	indented, err := c.indentFixture(text)
+	if err != nil {
+		return fmt.Errorf("error updating %s: %v", filename, err)
+	}
+	if err := os.WriteFile(filename, indented, 0644); err != nil {
+		return fmt.Errorf("error updating %s: %v", filename, err)
+	}
+	return nil
+}
+
+// notifyAnalysis explains StructCheck results in terms of the result type. Numbers that can't be
+// decoded into their destination without losing precision are always reported, since they may
+// round trip without a mismatch, as are timestamps whose format time.Time can't reproduce. When
+// the round trip failed, JSON keys that nearly match a field's tag and JSON keys dropped by
+// fields tagged "-" or unexported fields are reported as such, and struct fields are suggested
+// for the remaining JSON keys that have no destination field, so fixing the struct is a
+// copy-paste. Strings are masked as set by MaskPHI before they are analyzed.
+func (c *Comparer) notifyAnalysis(t Testing, text []byte, result interface{}, roundTripFailed bool) {
+	t.Helper()
+	value, err := getJSONNumberValue(c.stripJSONC(text))
+	if err != nil {
+		return
+	}
+	value = c.maskValue("", value, maskForAnalysis)
+	resultType := reflect.TypeOf(result)
+	for _, err := range findLossyNumbers("", resultType, value) {
+		t.Error(err)
+	}
+	for _, err := range findTimeFormatIssues("", resultType, value) {
+		t.Error(err)
+	}
+	if !roundTripFailed {
+		return
+	}
+	for _, nearMiss := range findTagNearMisses("", resultType, value) {
+		t.Error(nearMiss)
+	}
+	for _, dropped := range findDroppedKeys("", resultType, value) {
+		t.Error(dropped)
+	}
+	for _, err := range suggestFields(findUncapturedKeys("", resultType, value)) {
+		t.Error(err)
+	}
+}
+
+// explainUncapturedKeys replaces the missing values that a failed StructCheck round trip reports
+// at and below each JSON key that has no field to decode it into with a single error saying so,
+// since the missing field is why they differ, and the values are only its symptoms. Messages set
+// by WithMessageTemplate are left as they are.
+func (c *Comparer) explainUncapturedKeys(text []byte, result interface{}, errors []error) []error {
+	if len(errors) == 0 || c.messageTemplate != nil {
+		return errors
+	}
+	value, err := getJSONNumberValue(c.stripJSONC(text))
+	if err != nil {
+		return errors
+	}
+	uncaptured := findUncapturedKeys("", reflect.TypeOf(result), value)
+	if len(uncaptured) == 0 {
+		return errors
+	}
+	prefixes := make([][]pathSegment, len(uncaptured))
+	for i, u := range uncaptured {
+		prefixes[i] = splitPath(c.formatPath(u.location), c.pathFormat)
+	}
+	var explained []error
+	reported := map[int]bool{}
+	for _, err := range errors {
+		m, ok := err.(Mismatch)
+		i := -1
+		if ok && m.Kind == KindMissing {
+			i = segmentsPrefix(prefixes, splitPath(m.Path, c.pathFormat))
+		}
+		switch {
+		case i < 0:
+			explained = append(explained, err)
+		case !reported[i]:
+			reported[i] = true
+			explained = append(explained, c.uncapturedKey(uncaptured[i]))
+		}
+	}
+	return explained
+}
+
+// segmentsPrefix returns the index of the first of prefixes that segments starts with, or -1 if
+// it starts with none of them.
+func segmentsPrefix(prefixes [][]pathSegment, segments []pathSegment) int {
+	for i, prefix := range prefixes {
+		if len(prefix) > 0 && len(prefix) <= len(segments) && reflect.DeepEqual(prefix, segments[:len(prefix)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// uncapturedKey creates the Mismatch for a JSON key that has no field to decode it into.
+func (c *Comparer) uncapturedKey(u unmatchedKey) Mismatch {
+	path := c.formatPath(u.location)
+	m := Mismatch{
+		Path:     path,
+		Kind:     KindMissing,
+		Code:     KindMissing.Code(),
+		Expected: encodableNumbers(c.maskValue(u.location, u.value, maskForReport)),
+		Message:  fmt.Sprintf("%s present in JSON but has no field in %s", path, u.owner),
+	}
+	if c.errorCodes {
+		m.Message = fmt.Sprintf("[%s] %s", m.Code, m.Message)
 	}
+	return m
 }
 
 // EqualMap takes as its input two JSON byte slices and causes tests to fail as appropriate
@@ -66,20 +376,29 @@ func StructCheck(t Testing, filename string, result interface{}) {
 //      	b. 0.0 and nil
 //      	c. false and nil
 //      	d. empty slice and nil
+// The comparison is symmetric, so swapping json1 and json2 doesn't change whether they are
+// equal, except with AllowExtraKeys, which deliberately lets json2 add keys that json1 lacks, and
+// with placeholders in json1, as described on WithPlaceholders.
 func EqualMap(json1, json2 []byte) []error {
-	json1Map, err1 := getJSONMap(json1)
-	json2Map, err2 := getJSONMap(json2)
+	return NewComparer().EqualMap(json1, json2)
+}
+
+// EqualMap is like the package-level EqualMap, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) EqualMap(json1, json2 []byte) []error {
+	json1Map, err1 := c.getJSONMap(json1)
+	json2Map, err2 := c.getJSONMap(json2)
 	if err1 != nil || err2 != nil {
 		var errors []error
 		if err1 != nil {
-			errors = append(errors, fmt.Errorf("error unmarshalling json1: %v", err1))
+			errors = append(errors, c.invalidDocument("json1", err1))
 		}
 		if err2 != nil {
-			errors = append(errors, fmt.Errorf("error unmarshalling json2: %v", err2))
+			errors = append(errors, c.invalidDocument("json2", err2))
 		}
 		return errors
 	}
-	return compareMaps("", json1Map, json2Map)
+	return c.compareMaps("", json1Map, json2Map)
 }
 
 // EqualSlice takes as its input two JSON byte slices and causes tests to fail as appropriate
@@ -92,27 +411,74 @@ func EqualMap(json1, json2 []byte) []error {
 //      	b. 0.0 and nil
 //      	c. false and nil
 //      	d. empty slice and nil
+// The comparison is symmetric, so swapping json1 and json2 doesn't change whether they are
+// equal, except with AllowExtraKeys, which deliberately lets json2 add keys that json1 lacks, and
+// with placeholders in json1, as described on WithPlaceholders.
 func EqualSlice(json1, json2 []byte) []error {
-	json1Slice, err1 := getJSONSlice(json1)
-	json2Slice, err2 := getJSONSlice(json2)
+	return NewComparer().EqualSlice(json1, json2)
+}
+
+// EqualSlice is like the package-level EqualSlice, but compares the JSON using the options of
+// the Comparer.
+func (c *Comparer) EqualSlice(json1, json2 []byte) []error {
+	json1Slice, err1 := c.getJSONSlice(json1)
+	json2Slice, err2 := c.getJSONSlice(json2)
 	if err1 != nil || err2 != nil {
 		var errors []error
 		if err1 != nil {
-			errors = append(errors, fmt.Errorf("error unmarshalling json1: %v", err1))
+			errors = append(errors, c.invalidDocument("json1", err1))
 		}
 		if err2 != nil {
-			errors = append(errors, fmt.Errorf("error unmarshalling json2: %v", err2))
+			errors = append(errors, c.invalidDocument("json2", err2))
 		}
 		return errors
 	}
-	return compareSlices("", json1Slice, json2Slice)
+	return c.compareSlices("", json1Slice, json2Slice)
 }
 
-func notifyErrors(t Testing, filename string, errors []error) {
+// equalValues is like EqualMap and EqualSlice, but compares documents holding any JSON value.
+func (c *Comparer) equalValues(json1, json2 []byte) []error {
+	value1, value2, errors := c.unmarshalValues(json1, json2)
+	if len(errors) > 0 {
+		return errors
+	}
+	return c.compareValues("", value1, value2)
+}
+
+// unmarshalValues unmarshals json1 and json2, which may hold any JSON value.
+func (c *Comparer) unmarshalValues(json1, json2 []byte) (interface{}, interface{}, []error) {
+	var value1, value2 interface{}
+	err1 := c.unmarshal(json1, &value1)
+	err2 := c.unmarshal(json2, &value2)
+	var errors []error
+	if err1 != nil {
+		errors = append(errors, c.invalidDocument("json1", err1))
+	}
+	if err2 != nil {
+		errors = append(errors, c.invalidDocument("json2", err2))
+	}
+	return value1, value2, errors
+}
+
+func (c *Comparer) notifyErrors(t Testing, filename string, errors []error) {
+	if c.summaryOnly && !c.verbose && len(errors) > 0 {
+		t.Error(summarize(filename, errors, c.pathFormat))
+		return
+	}
 	if len(errors) > 0 {
 		t.Errorf("*** %d errors in %s", len(errors), filename)
 	}
-	for _, err := range errors {
+	if c.groupByKind {
+		for _, group := range groupErrors(errors, c.maxErrors) {
+			t.Error(group)
+		}
+		return
+	}
+	for i, err := range errors {
+		if c.maxErrors > 0 && i == c.maxErrors {
+			t.Errorf("… and %d more errors", len(errors)-i)
+			break
+		}
 		t.Error(err)
 	}
 }
@@ -131,35 +497,127 @@ func resultArgCheck(result interface{}) (bool, error) {
 	return isMapType, nil
 }
 
-func getJSONMap(text []byte) (map[string]interface{}, error) {
+func (c *Comparer) getJSONMap(text []byte) (map[string]interface{}, error) {
 	jsonMap := make(map[string]interface{})
-	return jsonMap, json.Unmarshal(text, &jsonMap)
+	return jsonMap, c.unmarshal(text, &jsonMap)
 }
 
-func getJSONSlice(text []byte) ([]interface{}, error) {
+func (c *Comparer) getJSONSlice(text []byte) ([]interface{}, error) {
 	jsonSlice := []interface{}{}
-	return jsonSlice, json.Unmarshal(text, &jsonSlice)
+	return jsonSlice, c.unmarshal(text, &jsonSlice)
+}
+
+// unmarshal is like unmarshalNumbers, but accepts the literals allowed by AllowNonFinite and
+// AllowLenientNumbers and the concatenated values allowed by AllowConcatenated if the Comparer
+// allows them, and rejects the documents rejected by checkText and validate.
+func (c *Comparer) unmarshal(text []byte, v interface{}) error {
+	text = c.stripJSONC(text)
+	if err := c.checkText(text); err != nil {
+		return err
+	}
+	if c.allowNonFinite || c.allowLenientNumbers {
+		text = c.quoteLiterals(text)
+	}
+	if c.allowConcatenated {
+		text = concatenatedArray(text)
+	}
+	if err := c.decodeNumbers(text, v); err != nil {
+		return explainSyntaxError(text, err)
+	}
+	if err := c.validate(text); err != nil {
+		return err
+	}
+	if !c.allowNonFinite && !c.allowLenientNumbers {
+		return nil
+	}
+	switch v := v.(type) {
+	case *map[string]interface{}:
+		for key, value := range *v {
+			(*v)[key] = unquoteLiterals(value)
+		}
+	case *[]interface{}:
+		for i, value := range *v {
+			(*v)[i] = unquoteLiterals(value)
+		}
+	case *interface{}:
+		*v = unquoteLiterals(*v)
+	}
+	return nil
 }
 
-func compareMaps(location string, map1, map2 map[string]interface{}) []error {
+// unmarshalNumbers is like json.Unmarshal, but decodes numbers as json.Number so that they
+// can be compared without losing precision.
+func unmarshalNumbers(text []byte, v interface{}) error {
+	if !json.Valid(text) {
+		return json.Unmarshal(text, v) // for the same syntax errors as json.Unmarshal
+	}
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+func (c *Comparer) compareMaps(location string, map1, map2 map[string]interface{}) []error {
 	var errors []error
 	for _, key := range keys(map1) {
-		errors = append(errors, compareValues(getLocation(location, key), map1[key], map2[key])...)
+		if c.sink.done() {
+			return errors
+		}
+		errors = c.collect(errors, c.compareKey(location, key, map1, map2))
+	}
+	if c.allowExtraKeys {
+		c.recordExtraKeys(location, map1, map2)
+		return errors
 	}
 	for _, key := range keys(map2) {
-		value1, ok := map1[key]
-		if !ok { // matched values were checked in the first loop, so only check missing ones here
-			errors = append(errors, compareValues(getLocation(location, key), value1, map2[key])...)
+		if c.sink.done() {
+			return errors
+		}
+		if _, ok := map1[key]; !ok { // matched values were checked in the first loop, so only check missing ones here
+			errors = c.collect(errors, c.compareKey(location, key, map1, map2))
 		}
 	}
 	return errors
 }
 
+// compareKey compares the values of key in map1 and map2, which are missing from a map that
+// doesn't have the key.
+func (c *Comparer) compareKey(location, key string, map1, map2 map[string]interface{}) []error {
+	keyLocation := getLocation(location, key)
+	value1, ok1 := map1[key]
+	value2, ok2 := map2[key]
+	if c.strictPresence && ok1 != ok2 {
+		ignored := c.ruleFor(keyLocation).ignore
+		c.countValue(ignored)
+		if ignored {
+			c.traceIgnored(keyLocation)
+			return nil
+		}
+		kind := KindMissing
+		if !ok1 {
+			kind = KindExtra
+		}
+		errors := []error{c.mismatch(kind, keyLocation, value1, value2, c.presentValue(keyLocation, value1, ok1), c.presentValue(keyLocation, value2, ok2))}
+		if c.trace != nil {
+			c.traceValue(keyLocation, value1, value2, pathRule{}, "", errors)
+		}
+		return errors
+	}
+	return c.compareValues(keyLocation, value1, value2)
+}
+
+// presentValue writes value for error messages, or <absent> if its key is missing.
+func (c *Comparer) presentValue(location string, value interface{}, ok bool) string {
+	if !ok {
+		return "<absent>"
+	}
+	return c.quoteValue(location, value)
+}
+
 func getLocation(location, key string) string {
 	if location == "" {
-		return key
+		return strings.TrimPrefix(quoteKey(key), ".")
 	}
-	return fmt.Sprintf("%s.%s", location, key)
+	return location + quoteKey(key)
 }
 
 func keys(v map[string]interface{}) []string {
@@ -171,38 +629,106 @@ func keys(v map[string]interface{}) []string {
 	return keys
 }
 
-func compareValues(location string, value1, value2 interface{}) []error {
+func (c *Comparer) compareValues(location string, value1, value2 interface{}) []error {
+	return c.compareValuesWithRule(location, value1, value2, c.ruleFor(location))
+}
+
+// compareValuesWithRule is like compareValues, with the rule for location already merged.
+func (c *Comparer) compareValuesWithRule(location string, value1, value2 interface{}, rule pathRule) []error {
+	c.countValue(rule.ignore)
+	if rule.ignore {
+		c.traceIgnored(location)
+		return nil
+	}
+	errors, decidedBy := c.compareWithRule(location, value1, value2, rule)
+	if c.trace != nil {
+		c.traceValue(location, value1, value2, rule, decidedBy, errors)
+	}
+	return errors
+}
+
+// compareWithRule compares the values at location, which rule doesn't ignore. It also returns a
+// description of the rule that decided the comparison in place of comparing the values by type,
+// such as "money", or "" if none did.
+func (c *Comparer) compareWithRule(location string, value1, value2 interface{}, rule pathRule) ([]error, string) {
+	if rule.scrub != "" {
+		return c.compareScrubbed(location, value1, value2, rule.scrub), "scrubbed"
+	}
+	if rule.hash != nil {
+		return c.compareHashes(location, value1, value2, rule.hash), "hashed"
+	}
+	if token, m, ok := c.placeholder(value1); ok {
+		return c.matchPlaceholder(location, token, m, value2), fmt.Sprintf("matched by placeholder %s", token)
+	}
+	if rule.matcher != nil {
+		return c.matchValues(location, rule.matcher, value1, value2), fmt.Sprintf("matched by %s", rule.matcher)
+	}
+	if timeRule := rule.time.merge(c.time); timeRule.enabled() {
+		if errors, ok := c.compareTimes(location, value1, value2, timeRule); ok {
+			return errors, "timestamps"
+		}
+	}
+	if rule.money {
+		if errors, ok := c.compareMoney(location, value1, value2, rule); ok {
+			return errors, "money"
+		}
+	}
+	if rule.numericString {
+		n1, ok1 := numericValue(value1)
+		n2, ok2 := numericValue(value2)
+		if ok1 && ok2 {
+			if !c.numberEqual(n1, n2, rule) {
+				return []error{c.notifyMismatch(KindValue, location, value1, value2)}, "numeric strings"
+			}
+			return nil, "numeric strings"
+		}
+	}
 	switch v1 := value1.(type) {
 	case bool:
-		if !boolEqual(v1, value2) {
-			return []error{notifyError(location, value1, value2)}
+		if !c.boolEqual(v1, value2) {
+			return []error{c.notifyError(location, value1, value2)}, ""
 		}
-	case float64:
-		if !floatEqual(v1, value2) {
-			return []error{notifyError(location, value1, value2)}
+	case json.Number:
+		if !c.numberEqual(v1, value2, rule) {
+			return []error{c.notifyError(location, value1, value2)}, ""
 		}
 	case map[string]interface{}:
 		v2, ok := value2.(map[string]interface{})
-		if value2 != nil && !ok {
-			return []error{notifyError(location, value1, value2)}
+		if value2 != nil && !ok || value2 == nil && (c.strict || isMapEmpty(v1) && !c.equalsNil(v1)) {
+			return []error{c.notifyError(location, value1, value2)}, ""
 		}
-		return compareMaps(location, v1, v2)
+		return c.compareMaps(location, v1, v2), ""
 	case string:
-		if !stringEqual(v1, value2) {
-			return []error{notifyError(location, value1, value2)}
+		if !c.stringEqual(v1, value2, rule) {
+			return []error{c.notifyError(location, value1, value2)}, ""
 		}
 	case nil:
-		if !isEmpty(value2) {
-			return []error{notifyError(location, value1, value2)}
+		if v2, ok := value2.(map[string]interface{}); ok && !c.strict && (!isMapEmpty(v2) || c.equalsNil(v2)) {
+			return c.compareMaps(location, nil, v2), "" // the same way as an object compared with nil
+		}
+		if value2 != nil && (c.strict || !c.equalsNil(value2)) {
+			return []error{c.notifyError(location, value1, value2)}, ""
 		}
 	default:
-		return compareSlices(location, value1, value2)
+		if pairs, ok := keyedPairs(value1, value2, rule.arrayKey); ok {
+			return c.compareKeyedSlices(location, pairs), ""
+		}
+		return c.compareSlices(location, value1, value2), ""
 	}
-	return nil
+	return nil, ""
+}
+
+func (c *Comparer) notifyError(location string, value1, value2 interface{}) error {
+	return c.notifyMismatch(mismatchKind(value1, value2), location, value1, value2)
 }
 
-func notifyError(location string, value1, value2 interface{}) error {
-	return fmt.Errorf("%s mismatch. %v vs. %v", location, quoteString(value1), quoteString(value2))
+// notifyMismatch is like notifyError, for differences whose kind doesn't follow from the types
+// of the values.
+func (c *Comparer) notifyMismatch(kind MismatchKind, location string, value1, value2 interface{}) error {
+	if c.jsonExcerpts && (isContainer(value1) || isContainer(value2)) {
+		return c.mismatch(kind, location, value1, value2, c.excerpt(location, value1), c.excerpt(location, value2))
+	}
+	return c.mismatch(kind, location, value1, value2, c.quoteValue(location, value1), c.quoteValue(location, value2))
 }
 
 func quoteString(v interface{}) string {
@@ -213,18 +739,98 @@ func quoteString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
-func boolEqual(value1 bool, value2 interface{}) bool {
-	return value1 == value2 || !value1 && value2 == nil
+func (c *Comparer) boolEqual(value1 bool, value2 interface{}) bool {
+	return value1 == value2 || !c.strict && !value1 && value2 == nil
+}
+
+// numberEqual compares numbers exactly, however they are written, so 1e3, 1000 and 1000.0 are
+// equal while IDs beyond the precision of a float64 such as 9007199254740993 and
+// 9007199254740992 are different, and so is a tolerance. It compares numbers that aren't
+// integers as float64s with WithFloat64Decimals, and numbers with exponents too large to expand
+// as float64s.
+func (c *Comparer) numberEqual(value1 json.Number, value2 interface{}, rule pathRule) bool {
+	if value2 == nil {
+		return !c.strict && isEmpty(value1)
+	}
+	v2, ok := value2.(json.Number)
+	if !ok {
+		return false
+	}
+	if value1 == v2 || c.lexicalNumbers {
+		return value1 == v2
+	}
+	tolerance, percent := c.numericTolerance(rule)
+	if scale, ok := c.decimalScale(rule); ok {
+		return decimalEqual(value1, v2, scale, tolerance, percent)
+	}
+	r1, exact1 := exactNumber(value1)
+	r2, exact2 := exactNumber(v2)
+	if exact1 && exact2 && r1.Sign() == 0 && r2.Sign() == 0 && c.distinguishNegativeZero {
+		return strings.HasPrefix(string(value1), "-") == strings.HasPrefix(string(v2), "-")
+	}
+	if exact1 && exact2 && (r1.IsInt() && r2.IsInt() || !c.float64Decimals) {
+		return ratEqual(r1, r2, tolerance, percent)
+	}
+	f1, err1 := value1.Float64()
+	f2, err2 := v2.Float64()
+	if err1 != nil || err2 != nil || !isFinite(f1) || !isFinite(f2) {
+		return false
+	}
+	diff := math.Abs(f1 - f2)
+	return diff <= tolerance || diff <= percent/100*math.Max(math.Abs(f1), math.Abs(f2))
+}
+
+// numericTolerance returns the absolute and relative tolerance for numbers compared under rule.
+func (c *Comparer) numericTolerance(rule pathRule) (float64, float64) {
+	tolerance, percent := c.tolerance, c.percent
+	if rule.hasTolerance {
+		tolerance = rule.tolerance
+	}
+	if rule.hasPercent {
+		percent = rule.percent
+	}
+	return tolerance, percent
 }
 
-func floatEqual(value1 float64, value2 interface{}) bool {
-	return value1 == value2 || value1 == 0.0 && value2 == nil
+// decimalScale returns the scale for numbers compared under rule as decimals, if they are.
+func (c *Comparer) decimalScale(rule pathRule) (int, bool) {
+	if rule.hasScale {
+		return rule.scale, true
+	}
+	return c.scale, c.hasScale
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// maxExactExponent limits the exponents exactNumber accepts, since the size of the value grows
+// with the exponent.
+const maxExactExponent = 1000
+
+// exactNumber returns the exact value of n, unless its exponent is too large to expand.
+func exactNumber(n json.Number) (*big.Rat, bool) {
+	if i := strings.IndexAny(string(n), "eE"); i >= 0 {
+		exp, err := strconv.Atoi(string(n[i+1:]))
+		if err != nil || exp > maxExactExponent || exp < -maxExactExponent {
+			return nil, false
+		}
+	}
+	return new(big.Rat).SetString(string(n))
 }
 
 func isEmpty(value interface{}) bool {
 	if value == "" || value == nil || value == 0.0 || value == false {
 		return true
 	}
+	if number, ok := value.(json.Number); ok {
+		// as in the comparison of numbers, underflowing values such as 1e-400 aren't zero
+		if r, ok := exactNumber(number); ok {
+			return r.Sign() == 0
+		}
+		f, err := number.Float64()
+		return err == nil && f == 0
+	}
 	if mapV, ok := value.(map[string]interface{}); ok {
 		return isMapEmpty(mapV)
 	}
@@ -232,6 +838,27 @@ func isEmpty(value interface{}) bool {
 	return rv.Kind() == reflect.Slice && rv.Len() == 0
 }
 
+// equalsNil reports whether the lenient rules consider value equal to nil, which depends on
+// DistinguishEmptyObjects and DistinguishDeepEmptyObjects for objects.
+func (c *Comparer) equalsNil(value interface{}) bool {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return isEmpty(value)
+	}
+	if len(m) == 0 {
+		return !c.distinguishEmptyObjects
+	}
+	if c.distinguishDeepEmpty {
+		return false
+	}
+	for _, item := range m {
+		if !c.equalsNil(item) {
+			return false
+		}
+	}
+	return true
+}
+
 func isMapEmpty(value map[string]interface{}) bool {
 	for _, key := range keys(value) {
 		if !isEmpty(value[key]) {
@@ -241,27 +868,37 @@ func isMapEmpty(value map[string]interface{}) bool {
 	return true
 }
 
-func stringEqual(value1 string, value2 interface{}) bool {
-	return value1 == value2 || value1 == "" && value2 == nil
+func (c *Comparer) stringEqual(value1 string, value2 interface{}, rule pathRule) bool {
+	v2, ok := value2.(string)
+	if !ok || value1 == v2 {
+		return value1 == value2 || !c.strict && value1 == "" && value2 == nil
+	}
+	if c.normalizeUnicode != nil {
+		value1, v2 = c.normalizeUnicode(value1), c.normalizeUnicode(v2)
+	}
+	if rule.ignoreCase {
+		return strings.EqualFold(value1, v2)
+	}
+	return value1 == v2
 }
 
-func compareSlices(location string, value1, value2 interface{}) []error {
+func (c *Comparer) compareSlices(location string, value1, value2 interface{}) []error {
 	rv1 := reflect.ValueOf(value1)
 	rv2 := reflect.ValueOf(value2)
-	if rv1.Kind() != reflect.Slice || (rv2.Kind() != reflect.Slice && rv2 != nilVal) {
-		return []error{notifyError(location, value1, value2)}
+	if rv1.Kind() != reflect.Slice || (rv2.Kind() != reflect.Slice && rv2 != nilVal) || c.strict && rv2 == nilVal {
+		return []error{c.notifyError(location, value1, value2)}
 	}
 	len1 := sliceLen(rv1)
 	if len1 != sliceLen(rv2) {
-		return []error{notifyError(location, value1, value2)}
+		return []error{c.notifyError(location, value1, value2)}
 	}
 	if len1 == 0 {
 		return nil
 	}
 
 	var errors []error
-	for i := 0; i < len1; i++ {
-		errors = append(errors, compareValues(fmt.Sprintf("%s[%d]", location, i), rv1.Index(i).Interface(), rv2.Index(i).Interface())...)
+	for i := 0; i < len1 && !c.sink.done(); i++ {
+		errors = c.collect(errors, c.compareValues(fmt.Sprintf("%s[%d]", location, i), rv1.Index(i).Interface(), rv2.Index(i).Interface()))
 	}
 	return errors
 }
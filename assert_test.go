@@ -1,8 +1,11 @@
 package jsonassert
 
 import (
+	"embed"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +39,11 @@ type subStruct struct {
 	B string `json:"b"`
 }
 
+type nearMissStruct struct {
+	MemberID  string `json:"memberID"`
+	FirstName string `json:"firstName"`
+}
+
 type sliceStruct struct {
 	Item1 string `json:"item1"`
 	Item2 string `json:"item2"`
@@ -54,13 +62,29 @@ func TestStructCheck(t *testing.T) {
 		expectedErrors []error
 	}{
 		{"everything matches", "testdata/complete.json", &receiveStruct{}, nil},
-		{"nothing matches", "testdata/complete.json", &subStruct{}, []error{fmt.Errorf("*** 6 errors in testdata/complete.json"), fmt.Errorf("arr mismatch. [1 2 3] vs. <nil>"), fmt.Errorf("b-true mismatch. true vs. <nil>"), fmt.Errorf("num mismatch. 1 vs. <nil>"), fmt.Errorf(`obj.a mismatch. "val" vs. <nil>`), fmt.Errorf(`obj.b mismatch. "val2" vs. <nil>`), fmt.Errorf(`str mismatch. "2" vs. <nil>`)}},
+		{"nothing matches", "testdata/complete.json", &subStruct{}, []error{fmt.Errorf("*** 5 errors in testdata/complete.json"), fmt.Errorf("arr present in JSON but has no field in jsonassert.subStruct"), fmt.Errorf("b-true present in JSON but has no field in jsonassert.subStruct"), fmt.Errorf("num present in JSON but has no field in jsonassert.subStruct"), fmt.Errorf("obj present in JSON but has no field in jsonassert.subStruct"), fmt.Errorf("str present in JSON but has no field in jsonassert.subStruct"), fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tArr []string `json:\"arr\"`\n\tBTrue bool `json:\"b-true\"`\n\tNum float64 `json:\"num\"`\n\tObj map[string]interface{} `json:\"obj\"`\n\tStr string `json:\"str\"`")}},
 		{"empty values all gone", "testdata/noEmpty.json", &receiveStruct{}, nil},
 		{"empty values are null", "testdata/nulls.json", &receiveStruct{}, nil},
 		{"bad filename", "bogus.json", &receiveStruct{}, []error{fmt.Errorf("open bogus.json: The system cannot find the file specified.")}},
 		{"wrong result type", "testdata/nulls.json", &jsonComplete, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got *string")}},
 		{"different data types", "testdata/newDataTypes.json", &receiveStruct{}, []error{fmt.Errorf("error decoding json in testdata/newDataTypes.json: json: cannot unmarshal string into Go struct field receiveStruct.num of type float64")}},
 		{"slice", "testdata/array.json", &[]sliceStruct{}, nil},
+		{"lossy numbers", "testdata/lossyNumbers.json", &numbersStruct{}, []error{
+			fmt.Errorf("*** 1 errors in testdata/lossyNumbers.json"),
+			fmt.Errorf("id mismatch. 9007199254740993 vs. 9007199254740992"),
+			fmt.Errorf("id: 9007199254740993 loses precision when decoded into float64 field jsonassert.numbersStruct.ID (decodes as 9007199254740992); consider int64 or json.Number"),
+		}},
+		{"ambiguous embedded fields", "testdata/complete.json", &conflictStruct{}, []error{
+			fmt.Errorf(`ambiguous field "ID" in jsonassert.conflictStruct: promoted from Billing.ID and Shipping.ID at the same depth, so encoding/json ignores all of them`),
+		}},
+		{"tag near misses", "testdata/nearMiss.json", &nearMissStruct{}, []error{
+			fmt.Errorf("*** 3 errors in testdata/nearMiss.json"),
+			fmt.Errorf(`first_name mismatch. "a" vs. <nil>`),
+			fmt.Errorf(`memberId mismatch. "1" vs. <nil>`),
+			fmt.Errorf(`memberID mismatch. <nil> vs. "1"`),
+			fmt.Errorf("first_name: tag `firstName` on jsonassert.nearMissStruct.FirstName vs key `first_name`"),
+			fmt.Errorf("memberId: tag `memberID` on jsonassert.nearMissStruct.MemberID vs key `memberId`"),
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -106,7 +130,15 @@ func TestEqualMap(t *testing.T) {
 			fmt.Errorf(`str mismatch. "2" vs. 2`),
 			fmt.Errorf(`str-empty mismatch. "" vs. 0`),
 		}},
-		{"with children", `{"a": null}`, `{"a": {"1":"", "2":"b"}}`, []error{fmt.Errorf("a mismatch. <nil> vs. map[1: 2:b]")}},
+		{"html escapes", `{"a": "\u003cb\u003e \u0026 \u003c/b\u003e"}`, `{"a": "<b> & </b>"}`, nil},
+		{"unicode escapes", `{"a": "Jos\u00e9", "b": "\ud83d\ude00", "c": "\u00C9t\u00e9"}`, `{"a": "José", "b": "😀", "c": "Été"}`, nil},
+		{"different unicode escapes", `{"a": "\ud83d\ude00"}`, `{"a": "\ud83d\ude01"}`, []error{fmt.Errorf(`a mismatch. "😀" vs. "😁"`)}},
+		{"with children", `{"a": null}`, `{"a": {"1":"", "2":"b"}}`, []error{fmt.Errorf(`a.2 mismatch. <nil> vs. "b"`)}},
+		{"large integers", `{"id": 9007199254740993, "big": 123456789012345678901234567890}`, `{"id": 9007199254740992, "big": 123456789012345678901234567890}`, []error{fmt.Errorf("id mismatch. 9007199254740993 vs. 9007199254740992")}},
+		{"integer and decimal", `{"a": 1, "b": 0, "c": 1e0}`, `{"a": 1.0, "b": -0.0, "c": 1}`, nil},
+		{"scientific notation", `{"a": 1e3, "b": 1000, "c": 1E+3, "d": -2.5e1, "e": 12345678901234567890e-2}`, `{"a": 1000.0, "b": 1e3, "c": 1000, "d": -25, "e": 123456789012345678.9}`, nil},
+		{"large integer and decimal", `{"a": 9007199254740993}`, `{"a": 9007199254740992.0}`, []error{fmt.Errorf("a mismatch. 9007199254740993 vs. 9007199254740992.0")}},
+		{"huge exponents", `{"a": 1e100000, "b": 1e100000}`, `{"a": 1e100000, "b": 1e99999}`, []error{fmt.Errorf("b mismatch. 1e100000 vs. 1e99999")}},
 		{"invalid file 1", `{`, jsonComplete, []error{fmt.Errorf("error unmarshalling json1: unexpected end of JSON input")}},
 		{"invalid file 2", jsonComplete, `{`, []error{fmt.Errorf("error unmarshalling json2: unexpected end of JSON input")}},
 	}
@@ -136,7 +168,7 @@ func TestEqualSlice(t *testing.T) {
 			fmt.Errorf(`[1].item1 mismatch. "value3" vs. 3`),
 			fmt.Errorf(`[1].item2 mismatch. "" vs. 4`),
 		}},
-		{"with children", `[{"a": null}]`, `[{"a": {"1":"", "2":"b"}}]`, []error{fmt.Errorf("[0].a mismatch. <nil> vs. map[1: 2:b]")}},
+		{"with children", `[{"a": null}]`, `[{"a": {"1":"", "2":"b"}}]`, []error{fmt.Errorf(`[0].a.2 mismatch. <nil> vs. "b"`)}},
 		{"invalid file 1", `[`, sliceComplete, []error{fmt.Errorf("error unmarshalling json1: unexpected end of JSON input")}},
 		{"invalid file 2", sliceComplete, `[`, []error{fmt.Errorf("error unmarshalling json2: unexpected end of JSON input")}},
 	}
@@ -178,3 +210,125 @@ func (t *fakeTester) Errorf(format string, args ...interface{}) {
 	t.errors = append(t.errors, fmt.Errorf(format, args...))
 }
 func (t *fakeTester) Helper() {}
+
+func TestStructCheckBytes(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		result         interface{}
+		opts           []Option
+		expectedErrors []error
+	}{
+		{"everything matches", jsonComplete, &receiveStruct{}, nil, nil},
+		{"mismatch", `{"a": "val", "c": 1}`, &subStruct{}, nil, []error{
+			fmt.Errorf("*** 1 errors in payload"),
+			fmt.Errorf("c present in JSON but has no field in jsonassert.subStruct"),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tC float64 `json:\"c\"`"),
+		}},
+		{"nested key without a field", `{"obj": {"a": "val", "c": {"d": [1, 2]}}}`, &receiveStruct{}, nil, []error{
+			fmt.Errorf("*** 1 errors in payload"),
+			fmt.Errorf("obj.c present in JSON but has no field in jsonassert.subStruct"),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tC map[string]interface{} `json:\"c\"`"),
+		}},
+		{"key without a field in pointer format", `{"obj": {"c": [1, 2]}}`, &receiveStruct{}, []Option{WithPathFormat(PathPointer), WithErrorCodes()}, []error{
+			fmt.Errorf("*** 1 errors in payload"),
+			fmt.Errorf("[JA001] /obj/c present in JSON but has no field in jsonassert.subStruct"),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tC []float64 `json:\"c\"`"),
+		}},
+		{"invalid json", `{"a": 1}`, &subStruct{}, nil, []error{fmt.Errorf("error decoding json in payload: json: cannot unmarshal number into Go struct field subStruct.a of type string")}},
+		{"wrong result type", `{}`, subStruct{}, nil, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got jsonassert.subStruct")}},
+		{"too large", `{"a": "val"}`, &subStruct{}, []Option{WithMaxSize(8)}, []error{fmt.Errorf("payload: document exceeds 8 bytes")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			NewComparer(tt.opts...).StructCheckBytes(fakeT, "payload", []byte(tt.data), tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("connection reset")
+}
+
+func TestStructCheckReader(t *testing.T) {
+	tests := []struct {
+		name           string
+		r              io.Reader
+		opts           []Option
+		expectedErrors []error
+	}{
+		{"everything matches", strings.NewReader(jsonComplete), nil, nil},
+		{"too large", strings.NewReader(jsonComplete), []Option{WithMaxSize(8)}, []error{fmt.Errorf("body: document exceeds 8 bytes")}},
+		{"read error", errReader{}, nil, []error{fmt.Errorf("error reading body: connection reset")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			NewComparer(tt.opts...).StructCheckReader(fakeT, "body", tt.r, &receiveStruct{})
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+//go:embed testdata/complete.json testdata/sidecar
+var embeddedFixtures embed.FS
+
+func TestStructCheckFS(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparer       *Comparer
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"everything matches", NewComparer(), "testdata/complete.json", &receiveStruct{}, nil},
+		{"sidecar rules applied", NewComparer(), "testdata/sidecar/complete.json", &subStruct{}, nil},
+		{"bad sidecar rules", NewComparer(), "testdata/sidecar/badRules.json", &receiveStruct{}, []error{
+			fmt.Errorf(`error parsing config testdata/sidecar/badRules.json.rules: json: unknown field "ignored"`),
+		}},
+		{"missing file", NewComparer(), "testdata/bogus.json", &receiveStruct{}, []error{fmt.Errorf("open testdata/bogus.json: file does not exist")}},
+		{"too large", NewComparer(WithMaxSize(8)), "testdata/complete.json", &receiveStruct{}, []error{fmt.Errorf("testdata/complete.json exceeds 8 bytes")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			tt.comparer.StructCheckFS(fakeT, embeddedFixtures, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestStructCheckGlob(t *testing.T) {
+	tests := []struct {
+		name           string
+		pattern        string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"each file checked", "testdata/glob/*.json", &subStruct{}, []error{
+			fmt.Errorf("*** 1 errors in testdata/glob/second.json"),
+			fmt.Errorf("c present in JSON but has no field in jsonassert.subStruct"),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tC float64 `json:\"c\"`"),
+		}},
+		{"directories skipped", "testdata/glob/*", &subStruct{}, []error{
+			fmt.Errorf("*** 1 errors in testdata/glob/second.json"),
+			fmt.Errorf("c present in JSON but has no field in jsonassert.subStruct"),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tC float64 `json:\"c\"`"),
+		}},
+		{"fresh result for each file", "testdata/glob/*/*.json", &subStruct{B: "stale"}, nil},
+		{"no matches", "testdata/glob/*.yaml", &subStruct{}, []error{fmt.Errorf("no files match testdata/glob/*.yaml")}},
+		{"bad pattern", "testdata/glob/[", &subStruct{}, []error{fmt.Errorf("invalid pattern testdata/glob/[: syntax error in pattern")}},
+		{"wrong result type", "testdata/glob/*.json", subStruct{}, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got jsonassert.subStruct")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			StructCheckGlob(fakeT, tt.pattern, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize returns text in the JSON Canonicalization Scheme of RFC 8785, in which object
+// keys are sorted, insignificant whitespace is removed, and numbers and strings are written
+// in a single way. Documents that are the same JSON value have the same canonical form, so
+// canonical golden files can be compared byte for byte before falling back to EqualMap:
+//   if !bytes.Equal(golden, canonical) {
+//     jsonassert.RequireEqualMap(t, golden, canonical)
+//   }
+// As the scheme requires, numbers are written as the nearest float64 in the notation of
+// JavaScript, so 1.0 and 1e0 become 1, but integers beyond 2^53, such as 9007199254740993, lose
+// precision. Text with duplicate keys, invalid UTF-8, escaped surrogates that aren't part of a
+// pair, such as "\ud800", or numbers beyond the range of float64 can't be canonicalized.
+func Canonicalize(text []byte) ([]byte, error) {
+	c := &Comparer{rejectDuplicateKeys: true, rejectInvalidUTF8: true}
+	value, err := c.getJSONValue(text)
+	if err != nil {
+		return nil, err
+	}
+	// encoding/json replaces them with U+FFFD, which would make different strings equal
+	if offset := unpairedSurrogate(text); offset >= 0 {
+		return nil, fmt.Errorf("unpaired surrogate %s at byte offset %d", text[offset:offset+6], offset)
+	}
+	var sb strings.Builder
+	if err := writeCanonical(&sb, value); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+// unpairedSurrogate returns the offset of the first \u escape in the strings of text that
+// encodes a UTF-16 surrogate that isn't part of a pair, or -1 if there is none.
+func unpairedSurrogate(text []byte) int {
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch ch := text[i]; {
+		case inString:
+			if ch == '"' {
+				inString = false
+			} else if ch == '\\' {
+				r := escapedRune(text, i)
+				switch {
+				case utf16.IsSurrogate(r) && r < 0xdc00:
+					if low := escapedRune(text, i+6); low < 0xdc00 || low > 0xdfff {
+						return i
+					}
+					i += 11
+				case utf16.IsSurrogate(r):
+					return i
+				default:
+					i++
+				}
+			}
+		case ch == '"':
+			inString = true
+		}
+	}
+	return -1
+}
+
+// escapedRune returns the code unit of the \u escape at text[i:], or -1 if there isn't one.
+func escapedRune(text []byte, i int) rune {
+	if i+6 > len(text) || text[i] != '\\' || text[i+1] != 'u' {
+		return -1
+	}
+	n, err := strconv.ParseUint(string(text[i+2:i+6]), 16, 16)
+	if err != nil {
+		return -1
+	}
+	return rune(n)
+}
+
+// writeCanonical writes value in the JSON Canonicalization Scheme.
+func writeCanonical(sb *strings.Builder, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		sb.WriteString("null")
+	case bool:
+		sb.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		s, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(s)
+	case string:
+		writeCanonicalString(sb, v)
+	case []interface{}:
+		sb.WriteString("[")
+		for i, element := range v {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			if err := writeCanonical(sb, element); err != nil {
+				return err
+			}
+		}
+		sb.WriteString("]")
+	case map[string]interface{}:
+		sb.WriteString("{")
+		for i, key := range utf16SortedKeys(v) {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			writeCanonicalString(sb, key)
+			sb.WriteString(":")
+			if err := writeCanonical(sb, v[key]); err != nil {
+				return err
+			}
+		}
+		sb.WriteString("}")
+	}
+	return nil
+}
+
+// utf16SortedKeys returns the keys of v sorted by their UTF-16 code units, as the scheme
+// requires.
+func utf16SortedKeys(v map[string]interface{}) []string {
+	keys := keys(v)
+	encoded := make(map[string][]uint16, len(keys))
+	for _, key := range keys {
+		encoded[key] = utf16.Encode([]rune(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := encoded[keys[i]], encoded[keys[j]]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return keys
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only quotes, backslashes and control
+// characters, as the scheme requires.
+func writeCanonicalString(sb *strings.Builder, s string) {
+	sb.WriteString(`"`)
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteString(`"`)
+}
+
+// canonicalNumber writes n as the nearest float64 in the notation of JavaScript's
+// Number.prototype.toString, as the scheme requires.
+func canonicalNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("number %s can't be canonicalized, since it isn't a finite float64", n)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	// the shortest digits that round trip, with f = 0.digits × 10^point
+	scientific := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent, _ := strings.Cut(scientific, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exponent)
+	point := e + 1
+	switch k := len(digits); {
+	case k <= point && point <= 21:
+		return sign + digits + strings.Repeat("0", point-k), nil
+	case 0 < point && point <= 21:
+		return sign + digits[:point] + "." + digits[point:], nil
+	case -6 < point && point <= 0:
+		return sign + "0." + strings.Repeat("0", -point) + digits, nil
+	}
+	if len(digits) > 1 {
+		digits = digits[:1] + "." + digits[1:]
+	}
+	expSign := "+"
+	if point-1 < 0 {
+		expSign = "-"
+	}
+	return fmt.Sprintf("%s%se%s%d", sign, digits, expSign, absInt(point-1)), nil
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		expected      string
+		expectedError string
+	}{
+		{"whitespace and order", `{ "b": [1, true, null], "a": {"d": "x", "c": {}} }`, `{"a":{"c":{},"d":"x"},"b":[1,true,null]}`, ""},
+		{"key order by UTF-16", `{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7}`,
+			"{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001F600\":5,\"\ufb33\":3}", ""},
+		{"strings", `"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/<&\u2028"`, "\"\u20ac$\\u000f\\nA'B\\\"\\\\\\\\\\\"/<&\u2028\"", ""},
+		{"duplicate keys", `{"a": 1, "a": 2}`, "", "duplicate key a"},
+		{"surrogate pair", `"\ud83d\ude00 \\ud800"`, "\"\U0001F600 \\\\ud800\"", ""},
+		{"lone high surrogate", `{"a": "x\ud800y"}`, "", `unpaired surrogate \ud800 at byte offset 8`},
+		{"high surrogate before another escape", `["\uD83D\u0041"]`, "", `unpaired surrogate \uD83D at byte offset 2`},
+		{"lone low surrogate", `["\n\udc00"]`, "", `unpaired surrogate \udc00 at byte offset 4`},
+		{"out of range", `[1e400]`, "", "number 1e400 can't be canonicalized, since it isn't a finite float64"},
+		{"invalid", `{`, "", "unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := Canonicalize([]byte(tt.text))
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("error = %v, want %s", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(canonical) != tt.expected {
+				t.Errorf("Canonicalize(%s) = %s, want %s", tt.text, canonical, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCanonicalNumber(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected string
+	}{
+		{"0", "0"},
+		{"-0", "0"},
+		{"4.50", "4.5"},
+		{"2e-3", "0.002"},
+		{"1E30", "1e+30"},
+		{"0.000000000000000000000000001", "1e-27"},
+		{"333333333.33333329", "333333333.3333333"},
+		{"9007199254740993", "9007199254740992"},
+		{"1e20", "100000000000000000000"},
+		{"1e21", "1e+21"},
+		{"123456789012345680000", "123456789012345680000"},
+		{"0.000001", "0.000001"},
+		{"0.0000001", "1e-7"},
+		{"-1.5e-7", "-1.5e-7"},
+		{"5e-324", "5e-324"},
+		{"1.7976931348623157e308", "1.7976931348623157e+308"},
+		{"-12.25", "-12.25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			s, err := canonicalNumber(json.Number(tt.number))
+			if err != nil || s != tt.expected {
+				t.Errorf("canonicalNumber(%s) = %s, %v, want %s", tt.number, s, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package jsonassert
+
+// Testing is the part of *testing.T that every assertion needs. Helper is included because it
+// only marks the function that calls it, so it can't be called through an adapter. Features
+// that need more of *testing.T check for the optional capabilities Fataler, Namer and Logger,
+// and degrade gracefully without them, so a minimal implementation still works everywhere.
+type Testing interface {
+	Reporter
+	Helper
+}
+
+// Reporter reports test failures.
+type Reporter interface {
+	Error(args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// Helper marks the calling function as a test helper, so failures are reported at the line of
+// the test that called it.
+type Helper interface {
+	Helper()
+}
+
+// Fataler stops the test, for the Require and Must variants.
+type Fataler interface {
+	FailNow()
+}
+
+// Namer names the test, for features that keep files per test.
+type Namer interface {
+	Name() string
+}
+
+// Logger logs diagnostics that aren't failures themselves, such as the tree and diff written
+// after the failures of a comparison.
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// failNow stops the test if t can, and reports whether it did.
+func failNow(t Testing) bool {
+	if f, ok := t.(Fataler); ok {
+		f.FailNow()
+		return true
+	}
+	return false
+}
+
+// testName returns the name of the test, or "" if t can't name it.
+func testName(t Testing) string {
+	if n, ok := t.(Namer); ok {
+		return n.Name()
+	}
+	return ""
+}
+
+// logf logs a diagnostic with t if it can, and otherwise reports it as an error. It is only used
+// for diagnostics of failures that were already reported, so the error doesn't fail a test that
+// would otherwise pass.
+func logf(t Testing, format string, args ...interface{}) {
+	t.Helper()
+	if l, ok := t.(Logger); ok {
+		l.Logf(format, args...)
+		return
+	}
+	t.Errorf(format, args...)
+}
@@ -0,0 +1,70 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+type capableTester struct {
+	fakeTester
+	failedNow bool
+}
+
+func (t *capableTester) FailNow()     { t.failedNow = true }
+func (t *capableTester) Name() string { return "TestCapable/case" }
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		tester    Testing
+		failedNow bool
+		testName  string
+	}{
+		{"minimal", &fakeTester{}, false, ""},
+		{"capable", &capableTester{}, true, "TestCapable/case"},
+		{"testing.T", t, false, t.Name()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.failedNow {
+				if !failNow(tt.tester) {
+					t.Errorf("failNow() = false, want true")
+				}
+			}
+			if name := testName(tt.tester); name != tt.testName {
+				t.Errorf("testName() = %q, want %q", name, tt.testName)
+			}
+		})
+	}
+}
+
+type loggingTester struct {
+	fakeTester
+	logs []string
+}
+
+func (t *loggingTester) Logf(format string, args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func TestLogf(t *testing.T) {
+	json1, json2 := []byte(`{"a": 1}`), []byte(`{"a": 2}`)
+	diff := "diff:\n--- json1\n+++ json2\n@@ -1,3 +1,3 @@\n {\n-  \"a\": 1\n+  \"a\": 2\n }\n"
+
+	logger := &loggingTester{}
+	NewComparer(WithDiff(false)).RequireEqualMap(logger, json1, json2)
+	checkErrors(t, []error{fmt.Errorf("*** 1 errors in json2"), fmt.Errorf("a mismatch. 1 vs. 2")}, logger.errors)
+	if len(logger.logs) != 1 || logger.logs[0] != diff {
+		t.Errorf("logs = %q, want [%q]", logger.logs, diff)
+	}
+
+	// the diff is reported as an error without Logf, and logged through FailFast
+	fakeT := &fakeTester{}
+	NewComparer(WithDiff(false)).RequireEqualMap(fakeT, json1, json2)
+	checkErrors(t, []error{fmt.Errorf("*** 1 errors in json2"), fmt.Errorf("a mismatch. 1 vs. 2"), fmt.Errorf("%s", diff)}, fakeT.errors)
+	logger = &loggingTester{}
+	NewComparer(WithDiff(false), FailFast()).Golden(logger, "testdata/complete.json", []byte(`{}`))
+	if len(logger.logs) != 1 {
+		t.Errorf("logs = %q, want the diff", logger.logs)
+	}
+}
@@ -0,0 +1,17 @@
+package jsonassert
+
+// Check is like StructCheck, but allocates the value the JSON is decoded into and returns it,
+// so the test can make further assertions on it:
+//   claim := jsonassert.Check[Claim](t, "testdata/claim.json")
+//   if claim.Status != "denied" {
+//     t.Errorf("status is %s", claim.Status)
+//   }
+// The options are applied as NewComparer applies them. T must be a struct, slice or map type;
+// any other type is reported as an error, as StructCheck reports it. The value is returned even
+// if the comparison fails, as far as it could be decoded.
+func Check[T any](t Testing, filename string, opts ...Option) T {
+	t.Helper()
+	var result T
+	NewComparer(opts...).StructCheck(t, filename, &result)
+	return result
+}
@@ -0,0 +1,34 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	fakeT := &fakeTester{}
+	result := Check[receiveStruct](fakeT, "testdata/complete.json")
+	checkErrors(t, nil, fakeT.errors)
+	if result.Str != "2" || !reflect.DeepEqual(result.Arr, []string{"1", "2", "3"}) || result.Obj.A != "val" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	fakeT = &fakeTester{}
+	slice := Check[[]sliceStruct](fakeT, "testdata/array.json")
+	checkErrors(t, nil, fakeT.errors)
+	if len(slice) == 0 {
+		t.Errorf("unexpected result %+v", slice)
+	}
+
+	fakeT = &fakeTester{}
+	sub := Check[subStruct](fakeT, "testdata/complete.json", WithIgnorePaths("arr", "b-true", "num", "obj", "str"))
+	checkErrors(t, nil, fakeT.errors)
+	if sub != (subStruct{}) {
+		t.Errorf("unexpected result %+v", sub)
+	}
+
+	fakeT = &fakeTester{}
+	Check[string](fakeT, "testdata/complete.json")
+	checkErrors(t, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got *string")}, fakeT.errors)
+}
@@ -0,0 +1,169 @@
+// Command jsonassert compares two JSON files as jsonassert.Compare does and prints their
+// differences, for shell scripts and CI jobs outside Go tests:
+//   jsonassert [flags] expected.json actual.json
+// Either file may be "-" to read it from standard input. It exits with status 0 if the documents
+// are equal, 1 if they differ and 2 if they can't be compared, as diff does. The flags mirror the
+// options of the library:
+//   -ignore path         ignore the values at path, as WithIgnorePaths does; may be repeated
+//   -array-key path=key  pair the elements of the arrays at path by key, as WithArrayKey does
+//   -strict              distinguish null from missing and zero values, as Strict does
+//   -allow-extra-keys    allow keys in actual that expected lacks, as AllowExtraKeys does
+//   -tolerance n         consider numbers equal within n, as WithTolerance does
+//   -config file         read options from a config file, as LoadConfig does
+//   -path-format format  write paths as dot, pointer or jsonpath, as WithPathFormat does
+//   -format format       write the differences as text, grouped, json or sarif, as Result.Report does
+//   -diff                also write a unified diff of the documents, with -format text or grouped
+// The JSONASSERT environment variables, such as JSONASSERT_MAX_ERRORS, apply as they do in
+// tests.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mypricehealth/jsonassert"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// run runs the command with args and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jsonassert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jsonassert [flags] expected.json actual.json")
+		flags.PrintDefaults()
+	}
+	var ignore, arrayKeys stringList
+	flags.Var(&ignore, "ignore", "ignore the values at `path`; may be repeated")
+	flags.Var(&arrayKeys, "array-key", "pair the elements of the arrays at path by key, given as `path=key`; may be repeated")
+	strict := flags.Bool("strict", false, "distinguish null from missing and zero values")
+	allowExtraKeys := flags.Bool("allow-extra-keys", false, "allow keys in actual that expected lacks")
+	tolerance := flags.Float64("tolerance", 0, "consider numbers equal when they differ by no more than `n`")
+	configFile := flags.String("config", "", "read options from a JSON or YAML config `file`")
+	pathFormat := flags.String("path-format", string(jsonassert.PathDot), "write paths as dot, pointer or jsonpath")
+	format := flags.String("format", string(jsonassert.ReportText), "write the differences as text, grouped, json or sarif")
+	diff := flags.Bool("diff", false, "also write a unified diff of the documents, with -format text or grouped")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	fail := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "jsonassert: "+format+"\n", args...)
+		return 2
+	}
+
+	// NewComparer skips invalid environment variables, which the command reports instead
+	if _, err := jsonassert.OptionsFromEnv(); err != nil {
+		return fail("%v", err)
+	}
+	var opts []jsonassert.Option
+	if *configFile != "" {
+		configOpts, err := jsonassert.LoadConfig(*configFile)
+		if err != nil {
+			return fail("%v", err)
+		}
+		opts = append(opts, configOpts...)
+	}
+	if len(ignore) > 0 {
+		opts = append(opts, jsonassert.WithIgnorePaths(ignore...))
+	}
+	for _, arrayKey := range arrayKeys {
+		path, key, ok := strings.Cut(arrayKey, "=")
+		if !ok || key == "" {
+			return fail("invalid -array-key %q: must be path=key", arrayKey)
+		}
+		opts = append(opts, jsonassert.WithArrayKey(path, key))
+	}
+	if *strict {
+		opts = append(opts, jsonassert.Strict())
+	}
+	if *allowExtraKeys {
+		opts = append(opts, jsonassert.AllowExtraKeys())
+	}
+	if *tolerance != 0 {
+		opts = append(opts, jsonassert.WithTolerance(*tolerance))
+	}
+	switch p := jsonassert.PathFormat(*pathFormat); p {
+	case jsonassert.PathDot, jsonassert.PathPointer, jsonassert.PathJSONPath:
+		opts = append(opts, jsonassert.WithPathFormat(p))
+	default:
+		return fail("unknown -path-format %q: must be dot, pointer or jsonpath", *pathFormat)
+	}
+	reportFormat := jsonassert.ReportFormat(*format)
+	switch reportFormat {
+	case jsonassert.ReportText, jsonassert.ReportGrouped, jsonassert.ReportJSON, jsonassert.ReportSARIF:
+	default:
+		return fail("unknown -format %q: must be text, grouped, json or sarif", *format)
+	}
+	if *diff && reportFormat != jsonassert.ReportText && reportFormat != jsonassert.ReportGrouped {
+		return fail("-diff can only be used with -format text or grouped")
+	}
+
+	file1, file2 := flags.Arg(0), flags.Arg(1)
+	if file1 == "-" && file2 == "-" {
+		return fail("only one of the files can be read from standard input")
+	}
+	json1, err := readInput(file1, stdin)
+	if err != nil {
+		return fail("%v", err)
+	}
+	json2, err := readInput(file2, stdin)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	c := jsonassert.NewComparer(opts...)
+	var result *jsonassert.Result
+	if file1 == "-" {
+		result = c.Compare(json1, json2)
+	} else {
+		// reports such as SARIF refer to the expected file
+		result = c.CompareFileBytes(file1, json1, json2)
+	}
+	if err := result.Report(stdout, reportFormat); err != nil {
+		return fail("%v", err)
+	}
+	for _, m := range result.Errors() {
+		if m.Kind == jsonassert.KindInvalid {
+			return 2
+		}
+	}
+	if *diff && !result.OK() {
+		if err := c.WriteDiff(stdout, json1, json2); err != nil {
+			return fail("%v", err)
+		}
+	}
+	if !result.OK() {
+		return 1
+	}
+	return 0
+}
+
+// readInput reads the file named name, or stdin if name is "-".
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(name)
+}
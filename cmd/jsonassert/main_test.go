@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	expected := write("expected.json", `{"a": 1, "b": "", "id": "x", "items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`)
+	actual := write("actual.json", `{"a": 2, "id": "y", "items": [{"id": 2, "v": 2}, {"id": 1, "v": 1}]}`)
+	equal := write("equal.json", `{"a": 1, "id": "x", "items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`)
+	config := write("config.json", `{"ignore": ["id"]}`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedStatus int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{"equal", []string{expected, equal}, "", 0, "", ""},
+		{"different", []string{expected, actual}, "", 1,
+			"*** 6 errors in json2\na mismatch. 1 vs. 2\nid mismatch. \"x\" vs. \"y\"\nitems[0].id mismatch. 1 vs. 2\nitems[0].v mismatch. 1 vs. 2\nitems[1].id mismatch. 2 vs. 1\nitems[1].v mismatch. 2 vs. 1\n", ""},
+		{"options", []string{"-ignore", "id", "-array-key", "items=id", "-tolerance", "1", expected, actual}, "", 0, "", ""},
+		{"strict", []string{"-strict", expected, equal}, "", 1, "*** 1 errors in json2\nb mismatch. \"\" vs. <nil>\n", ""},
+		{"config", []string{"-config", config, "-array-key", "items=id", expected, actual}, "", 1, "*** 1 errors in json2\na mismatch. 1 vs. 2\n", ""},
+		{"stdin", []string{"-ignore", "id", expected, "-"}, `{"a": 1, "items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`, 0, "", ""},
+		{"path format", []string{"-path-format", "pointer", "-ignore", "id", "-array-key", "items=id", expected, actual}, "", 1, "*** 1 errors in json2\n/a mismatch. 1 vs. 2\n", ""},
+		{"json format", []string{"-format", "json", "-ignore", "id", "-array-key", "items=id", expected, actual}, "", 1,
+			`{"ok":false,"mismatches":[{"path":"a","kind":"value","code":"JA003","expected":1,"actual":2,"message":"a mismatch. 1 vs. 2"}],"stats":{"values":10,"ignored":1,"mismatches":1}}` + "\n", ""},
+		{"grouped format", []string{"-format", "grouped", "-strict", expected, actual}, "", 1,
+			"*** 7 errors in json2\n1 missing value:\n    b mismatch. \"\" vs. <nil>\n6 value mismatches:\n    a mismatch. 1 vs. 2\n    id mismatch. \"x\" vs. \"y\"\n    items[0].id mismatch. 1 vs. 2\n    items[0].v mismatch. 1 vs. 2\n    items[1].id mismatch. 2 vs. 1\n    items[1].v mismatch. 2 vs. 1\n", ""},
+		{"diff", []string{"-diff", "-", equal}, `{"a": 2, "id": "x", "items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`, 1,
+			"*** 1 errors in json2\na mismatch. 2 vs. 1\n--- json1\n+++ json2\n@@ -1,5 +1,5 @@\n {\n-  \"a\": 2,\n+  \"a\": 1,\n   \"id\": \"x\",\n   \"items\": [\n     {\n", ""},
+		{"invalid JSON", []string{expected, "-"}, `{`, 2, "*** 1 errors in json2\nerror unmarshalling json2: unexpected end of JSON input\n", ""},
+		{"missing file", []string{expected, filepath.Join(dir, "missing.json")}, "", 2, "", "jsonassert: open " + filepath.Join(dir, "missing.json") + ": no such file or directory\n"},
+		{"both stdin", []string{"-", "-"}, "", 2, "", "jsonassert: only one of the files can be read from standard input\n"},
+		{"bad array key", []string{"-array-key", "items", expected, actual}, "", 2, "", "jsonassert: invalid -array-key \"items\": must be path=key\n"},
+		{"bad path format", []string{"-path-format", "xpath", expected, actual}, "", 2, "", "jsonassert: unknown -path-format \"xpath\": must be dot, pointer or jsonpath\n"},
+		{"bad format", []string{"-format", "xml", expected, actual}, "", 2, "", "jsonassert: unknown -format \"xml\": must be text, grouped, json or sarif\n"},
+		{"diff with json", []string{"-diff", "-format", "json", expected, actual}, "", 2, "", "jsonassert: -diff can only be used with -format text or grouped\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.expectedStatus {
+				t.Errorf("status = %d, want %d (stderr %q)", status, tt.expectedStatus, stderr.String())
+			}
+			if stdout.String() != tt.expectedStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.expectedStdout)
+			}
+			if stderr.String() != tt.expectedStderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.expectedStderr)
+			}
+		})
+	}
+}
+
+func TestRunBadEnv(t *testing.T) {
+	t.Setenv("JSONASSERT_MAX_ERRORS", "many")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"expected.json", "actual.json"}, strings.NewReader(""), &stdout, &stderr); status != 2 {
+		t.Errorf("status = %d, want 2", status)
+	}
+	expected := "jsonassert: invalid JSONASSERT_MAX_ERRORS \"many\": must be a non-negative integer\n"
+	if stderr.String() != expected {
+		t.Errorf("stderr = %q, want %q", stderr.String(), expected)
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"only-one.json"}, strings.NewReader(""), &stdout, &stderr); status != 2 {
+		t.Errorf("status = %d, want 2", status)
+	}
+	if !strings.HasPrefix(stderr.String(), "usage: jsonassert [flags] expected.json actual.json\n") {
+		t.Errorf("stderr = %q, want usage", stderr.String())
+	}
+}
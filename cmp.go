@@ -0,0 +1,37 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// RawMessageComparer returns an equality function for json.RawMessage values that compares them
+// as EqualMap does, for go-cmp, so RawMessage fields inside larger structs are compared
+// semantically by cmp.Diff and cmp.Equal:
+//   cmp.Diff(want, got, cmp.Comparer(jsonassert.RawMessageComparer(jsonassert.WithIgnorePaths("meta.*"))))
+// It returns a plain function rather than a cmp.Option, so that jsonassert doesn't depend on
+// go-cmp. Values that aren't valid JSON are equal only if their bytes are. go-cmp requires the
+// function to be symmetric, so options that aren't, such as AllowExtraKeys and WithPlaceholders,
+// shouldn't be used with it.
+func RawMessageComparer(opts ...Option) func(x, y json.RawMessage) bool {
+	equal := BytesComparer(opts...)
+	return func(x, y json.RawMessage) bool {
+		return equal(x, y)
+	}
+}
+
+// BytesComparer is like RawMessageComparer, but for []byte values. Since go-cmp applies it to
+// every []byte in the values compared, []byte values that aren't valid JSON are compared byte
+// for byte, as cmp does without it.
+func BytesComparer(opts ...Option) func(x, y []byte) bool {
+	c := NewComparer(opts...)
+	return func(x, y []byte) bool {
+		if bytes.Equal(x, y) {
+			return true
+		}
+		if !json.Valid(x) || !json.Valid(y) {
+			return false
+		}
+		return len(c.equalValues(x, y)) == 0
+	}
+}
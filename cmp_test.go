@@ -0,0 +1,42 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBytesComparer(t *testing.T) {
+	tests := []struct {
+		name     string
+		x        string
+		y        string
+		opts     []Option
+		expected bool
+	}{
+		{"same bytes", `{"a":1}`, `{"a":1}`, nil, true},
+		{"semantically equal", `{"a": 1, "b": ""}`, `{"a":1.0}`, nil, true},
+		{"different", `{"a": 1}`, `{"a":2}`, nil, false},
+		{"options", `{"a": 1, "id": 1}`, `{"a":1,"id":2}`, []Option{WithIgnorePaths("id")}, true},
+		{"arrays", `[1, 2]`, `[1,2]`, nil, true},
+		{"not JSON", `abc`, `abd`, nil, false},
+		{"same bytes not JSON", `abc`, `abc`, nil, true},
+		{"nil and empty", ``, ``, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal := BytesComparer(tt.opts...)
+			if got := equal([]byte(tt.x), []byte(tt.y)); got != tt.expected {
+				t.Errorf("BytesComparer()(%s, %s) = %v, want %v", tt.x, tt.y, got, tt.expected)
+			}
+			if got := equal([]byte(tt.y), []byte(tt.x)); got != tt.expected {
+				t.Errorf("BytesComparer()(%s, %s) = %v, want %v", tt.y, tt.x, got, tt.expected)
+			}
+			if got := RawMessageComparer(tt.opts...)(json.RawMessage(tt.x), json.RawMessage(tt.y)); got != tt.expected {
+				t.Errorf("RawMessageComparer()(%s, %s) = %v, want %v", tt.x, tt.y, got, tt.expected)
+			}
+		})
+	}
+	if !BytesComparer()(nil, []byte{}) {
+		t.Errorf("BytesComparer()(nil, []byte{}) = false, want true")
+	}
+}
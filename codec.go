@@ -0,0 +1,66 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// Codec is a JSON library, such as go-json or jsoniter, that StructCheck and the Equal
+// functions decode and encode with instead of encoding/json when it is set by WithCodec. Most
+// libraries match it with a small adapter, since their NewDecoder returns their own decoder
+// type:
+//   type goJSONCodec struct{}
+//
+//   func (goJSONCodec) Marshal(v interface{}) ([]byte, error)      { return gojson.Marshal(v) }
+//   func (goJSONCodec) Unmarshal(data []byte, v interface{}) error { return gojson.Unmarshal(data, v) }
+//   func (goJSONCodec) NewDecoder(r io.Reader) jsonassert.Decoder  { return gojson.NewDecoder(r) }
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder is the part of a Codec's streaming decoder that the Comparer uses, which
+// *json.Decoder implements.
+type Decoder interface {
+	UseNumber()
+	Decode(v interface{}) error
+}
+
+// WithCodec makes StructCheck decode fixtures into the result and re-encode the result with
+// codec, so the round trip is checked against the library used in production, and makes the
+// Equal functions decode documents with it. Documents are still checked for syntax errors by
+// encoding/json, so that they are reported the same way with every codec. WithMarshal takes
+// precedence over codec for re-encoding, and WithEscapeHTML doesn't apply to it.
+func WithCodec(codec Codec) Option {
+	return func(c *Comparer) {
+		c.codec = codec
+	}
+}
+
+// newDecoder returns a decoder for text from the Codec set by WithCodec, or from encoding/json.
+func (c *Comparer) newDecoder(text []byte) Decoder {
+	if c.codec != nil {
+		return c.codec.NewDecoder(bytes.NewReader(text))
+	}
+	return json.NewDecoder(bytes.NewReader(text))
+}
+
+// unmarshalValue is like json.Unmarshal, but decodes with the Codec set by WithCodec, if any.
+func (c *Comparer) unmarshalValue(text []byte, v interface{}) error {
+	if c.codec != nil {
+		return c.codec.Unmarshal(text, v)
+	}
+	return json.Unmarshal(text, v)
+}
+
+// decodeNumbers is like unmarshalNumbers, but decodes with the Codec set by WithCodec, if any.
+func (c *Comparer) decodeNumbers(text []byte, v interface{}) error {
+	if c.codec == nil || !json.Valid(text) {
+		return unmarshalNumbers(text, v)
+	}
+	d := c.newDecoder(text)
+	d.UseNumber()
+	return d.Decode(v)
+}
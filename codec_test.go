@@ -0,0 +1,76 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// recordingCodec is encoding/json with its calls counted, and optionally with unknown fields
+// disallowed.
+type recordingCodec struct {
+	marshals, unmarshals, decoders int
+	disallowUnknownFields          bool
+}
+
+func (r *recordingCodec) Marshal(v interface{}) ([]byte, error) {
+	r.marshals++
+	return json.Marshal(v)
+}
+
+func (r *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	r.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func (r *recordingCodec) NewDecoder(reader io.Reader) Decoder {
+	r.decoders++
+	d := json.NewDecoder(reader)
+	if r.disallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
+	return d
+}
+
+func TestWithCodec(t *testing.T) {
+	tests := []struct {
+		name               string
+		codec              *recordingCodec
+		check              func(c *Comparer, fakeT *fakeTester)
+		expectedErrors     []error
+		expectedMarshals   int
+		expectedUnmarshals int
+		expectedDecoders   int
+	}{
+		{"struct check", &recordingCodec{}, func(c *Comparer, fakeT *fakeTester) {
+			c.StructCheck(fakeT, "testdata/complete.json", &receiveStruct{})
+		}, nil, 1, 0, 3},
+		{"struct check with codec errors", &recordingCodec{disallowUnknownFields: true}, func(c *Comparer, fakeT *fakeTester) {
+			c.StructCheck(fakeT, "testdata/complete.json", &subStruct{})
+		}, []error{fmt.Errorf(`error decoding json in testdata/complete.json: json: unknown field "num"`)}, 0, 0, 1},
+		{"concatenated values", &recordingCodec{}, func(c *Comparer, fakeT *fakeTester) {
+			c.With(AllowConcatenated()).StructCheckBytes(fakeT, "lines", []byte("{\"a\": \"x\"}\n{\"a\": \"y\"}\n"), &[]subStruct{})
+		}, nil, 1, 2, 2},
+		{"equal", &recordingCodec{}, func(c *Comparer, fakeT *fakeTester) {
+			for _, err := range c.EqualMap([]byte(`{"a": 1}`), []byte(`{"a": 2}`)) {
+				fakeT.Error(err)
+			}
+		}, []error{fmt.Errorf("a mismatch. 1 vs. 2")}, 0, 0, 2},
+		{"syntax errors", &recordingCodec{}, func(c *Comparer, fakeT *fakeTester) {
+			for _, err := range c.EqualMap([]byte(`{"a": 1,}`), []byte(`{}`)) {
+				fakeT.Error(err)
+			}
+		}, []error{fmt.Errorf("error unmarshalling json1: invalid character '}' looking for beginning of object key string")}, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			tt.check(NewComparer(WithCodec(tt.codec)), fakeT)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+			if tt.codec.marshals != tt.expectedMarshals || tt.codec.unmarshals != tt.expectedUnmarshals || tt.codec.decoders != tt.expectedDecoders {
+				t.Errorf("marshals = %d, unmarshals = %d, decoders = %d, want %d, %d and %d", tt.codec.marshals, tt.codec.unmarshals, tt.codec.decoders, tt.expectedMarshals, tt.expectedUnmarshals, tt.expectedDecoders)
+			}
+		})
+	}
+}
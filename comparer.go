@@ -0,0 +1,328 @@
+package jsonassert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Comparer compares JSON documents using a set of options. The package-level functions use a
+// Comparer with no options, which applies the lenient rules described on EqualMap. Create one
+// with NewComparer.
+type Comparer struct {
+	strict                  bool
+	allowExtraKeys          bool
+	strictPresence          bool
+	distinguishEmptyObjects bool
+	distinguishDeepEmpty    bool
+	tolerance               float64
+	percent                 float64
+	scale                   int
+	hasScale                bool
+	numberFormat            NumberFormat
+	jsonExcerpts            bool
+	messageTemplate         *template.Template
+	errorCodes              bool
+	trace                   func(TraceEntry)
+	file                    string // the fixture checked by StructCheck
+	allowNonFinite          bool
+	allowLenientNumbers     bool
+	distinguishNegativeZero bool
+	float64Decimals         bool
+	lexicalNumbers          bool
+	rejectDuplicateKeys     bool
+	maskPHI                 bool
+	allowConcatenated       bool
+	allowJSONC              bool
+	rejectInvalidUTF8       bool
+	maxSize                 int64
+	escapeHTML              bool
+	indentPrefix            string
+	indent                  string
+	hasIndent               bool
+	marshal                 func(interface{}) ([]byte, error)
+	codec                   Codec
+	normalizeUnicode        func(string) string
+	time                    timeRule
+	rules                   []pathRule
+	placeholders            map[string]Matcher
+	regexpPlaceholders      *sync.Map // the Matcher of each <<REGEX:expr>> token, if enabled
+	pathFormat              PathFormat
+	update                  bool
+	maxErrors               int
+	verbose                 bool
+	failFast                bool
+	summaryOnly             bool
+	groupByKind             bool
+	diff                    bool
+	diffColor               bool
+	sink                    *mismatchSink // set on the copy made by Differences
+	stats                   *Stats        // set on the copy made by Compare
+	extraKeys               *[]string     // set on the copy made by Superset
+	configErr               error         // the invalid options and environment variables skipped
+}
+
+// Option configures a Comparer.
+type Option func(*Comparer)
+
+var (
+	defaultOptionsMu sync.RWMutex
+	defaultOptions   []Option
+)
+
+// SetDefaultOptions sets the options every new Comparer starts with, including the one used by
+// the package-level functions, so a TestMain can configure the comparison once for a whole
+// package. It replaces the defaults set by an earlier call, and calling it with no options
+// restores the lenient rules described on EqualMap.
+func SetDefaultOptions(opts ...Option) {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+	defaultOptions = append([]Option(nil), opts...)
+}
+
+// NewComparer returns a Comparer configured with the default options set by SetDefaultOptions,
+// then the given options, which override the defaults, and then the options returned by
+// OptionsFromEnv. An environment variable with an invalid value is skipped, and the assertions
+// of the Comparer that take a Testing report it as an error.
+func NewComparer(opts ...Option) *Comparer {
+	envOpts, envErr := envOptions()
+	defaultOptionsMu.RLock()
+	all := append([]Option(nil), defaultOptions...)
+	defaultOptionsMu.RUnlock()
+	all = append(append(all, opts...), envOpts...)
+	c := &Comparer{configErr: envErr}
+	for _, opt := range all {
+		opt(c)
+	}
+	return c
+}
+
+// With returns a copy of c with opts applied after its own options, so a table test can relax
+// one rule for one case without changing a Comparer shared by the other cases.
+func (c *Comparer) With(opts ...Option) *Comparer {
+	derived := *c
+	derived.rules = append([]pathRule(nil), c.rules...)
+	for _, opt := range opts {
+		opt(&derived)
+	}
+	return &derived
+}
+
+// Strict disables the rules that consider nil equal to the default value for its data type, so
+// "", 0, false, [] and {} are no longer equal to null or a missing key.
+func Strict() Option {
+	return func(c *Comparer) {
+		c.strict = true
+	}
+}
+
+// DistinguishEmptyObjects considers {} different from null and a missing key. By default they
+// are equal, like the other default values. Objects whose values are all empty are still equal
+// to null unless DistinguishDeepEmptyObjects is also set.
+func DistinguishEmptyObjects() Option {
+	return func(c *Comparer) {
+		c.distinguishEmptyObjects = true
+	}
+}
+
+// DistinguishDeepEmptyObjects considers objects whose values are all empty, such as
+// {"a": "", "b": {"c": null}}, different from null and a missing key. By default they are equal,
+// which can hide a missing sub-document. {} is still equal to null unless
+// DistinguishEmptyObjects is also set.
+func DistinguishDeepEmptyObjects() Option {
+	return func(c *Comparer) {
+		c.distinguishDeepEmpty = true
+	}
+}
+
+// StrictPresence considers a key that is in only one of the documents different from the other
+// document, even when its value is null, so {"a": null} and {} are no longer equal. Use it where
+// null and a missing key mean different things, such as PATCH requests where null clears a value
+// and a missing key leaves it alone. Values of keys in both documents are compared as usual,
+// so combine it with Strict to also tell null apart from "", 0 and false.
+func StrictPresence() Option {
+	return func(c *Comparer) {
+		c.strictPresence = true
+	}
+}
+
+// AllowExtraKeys ignores object keys that are only in json2, so json2 may add to json1 but not
+// take anything away from it.
+func AllowExtraKeys() Option {
+	return func(c *Comparer) {
+		c.allowExtraKeys = true
+	}
+}
+
+// WithTolerance considers two numbers equal when they differ by no more than tolerance.
+func WithTolerance(tolerance float64) Option {
+	return func(c *Comparer) {
+		c.tolerance = tolerance
+	}
+}
+
+// WithinPercent considers two numbers equal when they differ by no more than percent percent of
+// the larger of their magnitudes, so WithinPercent(0.01) accepts 10000 vs. 10001 and 0.0001 vs.
+// 0.00010001 alike. Numbers are equal if they are within either this or the tolerance set by
+// WithTolerance.
+func WithinPercent(percent float64) Option {
+	return func(c *Comparer) {
+		c.percent = percent
+	}
+}
+
+// WithNumericTolerance considers two numbers equal when they differ by no more than abs, or by
+// no more than rel times the larger of their magnitudes, for floating point values computed by
+// different languages that rarely match bit for bit. It sets both the tolerance of WithTolerance
+// and the percentage of WithinPercent, so WithNumericTolerance(1e-9, 1e-6) is the same as
+// WithTolerance(1e-9) with WithinPercent(1e-4). Given paths, it applies only to the numbers at
+// those paths, in place of the global tolerances, like a rule's Tolerance and Percent. Paths use
+// the notation described on WithIgnorePaths.
+func WithNumericTolerance(abs, rel float64, paths ...string) Option {
+	if len(paths) == 0 {
+		return func(c *Comparer) {
+			c.tolerance, c.percent = abs, rel*100
+		}
+	}
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].tolerance, rules[i].hasTolerance = abs, true
+		rules[i].percent, rules[i].hasPercent = rel*100, true
+	}
+	return withPathRules(rules)
+}
+
+// NormalizeUnicode compares strings after normalizing them with normalize, so that strings that
+// differ only in the Unicode form of their characters, such as "é" and "e\u0301", are equal.
+// Names from different source systems often differ this way. Pass the normalization form from
+// golang.org/x/text/unicode/norm:
+//   jsonassert.NormalizeUnicode(norm.NFC.String)
+// Error messages show the strings as they were written.
+func NormalizeUnicode(normalize func(string) string) Option {
+	return func(c *Comparer) {
+		c.normalizeUnicode = normalize
+	}
+}
+
+// WithIgnorePaths excludes the values at the given paths from the comparison. Paths use the same
+// notation as the locations in error messages, such as "meta.requestId" or "items[0].id". A "*"
+// matches any single key, or the rest of a key as in "*Amount", and "[*]" matches any array
+// index, so "items[*].updatedAt" ignores updatedAt in every element of items. A "**" matches
+// any number of keys and indexes, so "**.traceId" ignores traceId at any depth.
+func WithIgnorePaths(paths ...string) Option {
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].ignore = true
+	}
+	return withPathRules(rules)
+}
+
+// WithIgnoreCase compares the strings at the given paths without regard to case, for codes like
+// "TX" and "tx" whose casing varies between systems. Paths use the notation described on
+// WithIgnorePaths.
+func WithIgnoreCase(paths ...string) Option {
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].ignoreCase = true
+	}
+	return withPathRules(rules)
+}
+
+// WithNumericStrings compares the strings at the given paths as numbers when both are numbers
+// written with optional thousands separators, so "1,234.50", "1234.5" and the number 1234.5 are
+// equal. Data exported from spreadsheets is often written this way. Strings that aren't numbers
+// are compared as strings. Paths use the notation described on WithIgnorePaths.
+func WithNumericStrings(paths ...string) Option {
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].numericString = true
+	}
+	return withPathRules(rules)
+}
+
+// WithMatcher compares the values at path using m instead of comparing them with each other.
+// The values are equal when each value that is present matches. Path uses the notation
+// described on WithIgnorePaths.
+func WithMatcher(path string, m Matcher) Option {
+	rule := newPathRule(path)
+	rule.matcher = m
+	return withPathRules([]pathRule{rule})
+}
+
+// Matcher decides whether a single JSON value is acceptable. The value is a decoded JSON value:
+// a bool, json.Number, string, map[string]interface{}, []interface{} or nil.
+type Matcher interface {
+	Match(value interface{}) bool
+	// String describes the values the Matcher accepts for error messages.
+	String() string
+}
+
+// MatchRegexp returns a Matcher that accepts strings matching the regular expression expr. It
+// panics if expr can't be compiled.
+func MatchRegexp(expr string) Matcher {
+	return regexpMatcher{regexp.MustCompile(expr)}
+}
+
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m regexpMatcher) Match(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && m.re.MatchString(s)
+}
+
+func (m regexpMatcher) String() string {
+	return fmt.Sprintf("regexp %q", m.re)
+}
+
+// pathPattern matches the locations used in error messages against a path that may contain
+// wildcards.
+type pathPattern struct {
+	path string
+	re   *regexp.Regexp
+}
+
+func compilePathPattern(path string) pathPattern {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(path); i++ {
+		switch {
+		case strings.HasPrefix(path[i:], "**."):
+			sb.WriteString(`(.*\.)?`)
+			i += 2
+		case strings.HasPrefix(path[i:], "**"):
+			sb.WriteString(`.*`)
+			i++
+		case strings.HasPrefix(path[i:], "[*]"):
+			sb.WriteString(`\[\d+\]`)
+			i += 2
+		case path[i] == '*':
+			sb.WriteString(`[^.\[]*`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(path[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return pathPattern{path: path, re: regexp.MustCompile(sb.String())}
+}
+
+func (p pathPattern) match(location string) bool {
+	return p.re.MatchString(location)
+}
+
+func (c *Comparer) matchValues(location string, m Matcher, value1, value2 interface{}) []error {
+	var errors []error
+	for _, value := range []interface{}{value1, value2} {
+		if value != nil && !m.Match(value) {
+			errors = append(errors, c.mismatch(KindPattern, location, value1, value2, fmt.Sprint(m), c.quoteValue(location, value)))
+		}
+	}
+	return errors
+}
@@ -0,0 +1,220 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestComparerEqualMap(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"no options", nil, jsonComplete, jsonNulls, nil},
+		{"strict nulls", []Option{Strict()}, jsonComplete, jsonNulls, []error{
+			fmt.Errorf(`arr-empty mismatch. [] vs. <nil>`),
+			fmt.Errorf(`b-false mismatch. false vs. <nil>`),
+			fmt.Errorf(`num-empty mismatch. 0 vs. <nil>`),
+			fmt.Errorf(`obj-empty mismatch. map[] vs. <nil>`),
+			fmt.Errorf(`str-empty mismatch. "" vs. <nil>`),
+		}},
+		{"strict nulls on the left", []Option{Strict()}, jsonNulls, jsonComplete, []error{
+			fmt.Errorf(`arr-empty mismatch. <nil> vs. []`),
+			fmt.Errorf(`b-false mismatch. <nil> vs. false`),
+			fmt.Errorf(`num-empty mismatch. <nil> vs. 0`),
+			fmt.Errorf(`obj-empty mismatch. <nil> vs. map[]`),
+			fmt.Errorf(`str-empty mismatch. <nil> vs. ""`),
+		}},
+		{"strict same", []Option{Strict()}, jsonComplete, jsonComplete, nil},
+		{"strict missing keys and elements", []Option{Strict()}, `{"a": {"b": [0, ""]}, "c": false}`, `{"a": {"b": [null, null], "d": {}}}`, []error{
+			fmt.Errorf(`a.b[0] mismatch. 0 vs. <nil>`),
+			fmt.Errorf(`a.b[1] mismatch. "" vs. <nil>`),
+			fmt.Errorf(`a.d mismatch. <nil> vs. map[]`),
+			fmt.Errorf(`c mismatch. false vs. <nil>`),
+		}},
+		{"empty objects", []Option{DistinguishEmptyObjects()}, `{"a": {}, "b": {"c": ""}, "d": null, "e": {"f": {}}}`, `{"a": null, "d": {}}`, []error{
+			fmt.Errorf(`a mismatch. map[] vs. <nil>`),
+			fmt.Errorf(`d mismatch. <nil> vs. map[]`),
+			fmt.Errorf(`e mismatch. map[f:map[]] vs. <nil>`),
+		}},
+		{"deep empty objects", []Option{DistinguishDeepEmptyObjects()}, `{"a": {}, "b": {"c": ""}, "d": null, "e": {"f": 1}}`, `{"a": null, "d": {"g": [], "h": null}}`, []error{
+			fmt.Errorf(`b mismatch. map[c:] vs. <nil>`),
+			fmt.Errorf(`d mismatch. <nil> vs. map[g:[] h:<nil>]`),
+			fmt.Errorf(`e.f mismatch. 1 vs. <nil>`),
+		}},
+		{"empty and deep empty objects", []Option{DistinguishEmptyObjects(), DistinguishDeepEmptyObjects()}, `{"a": {}, "b": {"c": ""}, "d": {"e": ""}}`, `{"d": {}}`, []error{
+			fmt.Errorf(`a mismatch. map[] vs. <nil>`),
+			fmt.Errorf(`b mismatch. map[c:] vs. <nil>`),
+		}},
+		{"strict presence", []Option{StrictPresence()}, `{"a": null, "b": null, "c": {"d": null}, "e": "", "f": null}`, `{"b": null, "c": {}, "e": null, "g": null, "h": 0}`, []error{
+			fmt.Errorf(`a mismatch. <nil> vs. <absent>`),
+			fmt.Errorf(`c.d mismatch. <nil> vs. <absent>`),
+			fmt.Errorf(`f mismatch. <nil> vs. <absent>`),
+			fmt.Errorf(`g mismatch. <absent> vs. <nil>`),
+			fmt.Errorf(`h mismatch. <absent> vs. 0`),
+		}},
+		{"strict presence with ignored and extra keys", []Option{StrictPresence(), WithIgnorePaths("a"), AllowExtraKeys()}, `{"a": null, "b": 1}`, `{"b": 1, "c": null}`, nil},
+		{"outside tolerance", []Option{WithTolerance(0.01)}, `{"a": 1.5}`, `{"a": 1.52}`, []error{fmt.Errorf("a mismatch. 1.5 vs. 1.52")}},
+		{"within tolerance", []Option{WithTolerance(0.01)}, `{"a": 1.5}`, `{"a": 1.505}`, nil},
+		{"unicode not normalized", nil, `{"a": "Jos\u00e9"}`, `{"a": "Jose\u0301"}`, []error{fmt.Errorf("a mismatch. \"José\" vs. \"Jose\u0301\"")}},
+		{"unicode normalized", []Option{NormalizeUnicode(composeAcute)}, `{"a": "Jos\u00e9", "b": "x"}`, `{"a": "Jose\u0301", "b": "y"}`, []error{fmt.Errorf(`b mismatch. "x" vs. "y"`)}},
+		{"ignore case", []Option{WithIgnoreCase("members[*].state")}, `{"members": [{"state": "TX", "name": "Ann"}]}`, `{"members": [{"state": "tx", "name": "ann"}]}`, []error{
+			fmt.Errorf(`members[0].name mismatch. "Ann" vs. "ann"`),
+		}},
+		{"ignore case with normalization", []Option{WithIgnoreCase("a"), NormalizeUnicode(composeAcute)}, `{"a": "JOS\u00c9"}`, `{"a": "jose\u0301"}`, nil},
+		{"within percent", []Option{WithinPercent(0.01)}, `{"a": 10000, "b": 0.0001, "c": -5}`, `{"a": 10001, "b": 0.00010001, "c": -5.0005}`, nil},
+		{"outside percent", []Option{WithinPercent(0.01)}, `{"a": 10000, "b": 0.0001, "c": 0}`, `{"a": 10002, "b": 0.00010002, "c": 0.000001}`, []error{
+			fmt.Errorf("a mismatch. 10000 vs. 10002"),
+			fmt.Errorf("b mismatch. 0.0001 vs. 0.00010002"),
+			fmt.Errorf("c mismatch. 0 vs. 0.000001"),
+		}},
+		{"within tolerance or percent", []Option{WithTolerance(0.001), WithinPercent(0.01)}, `{"a": 10000, "c": 0}`, `{"a": 10001, "c": 0.000001}`, nil},
+		{"numeric tolerance", []Option{WithNumericTolerance(1e-9, 1e-6)}, `{"a": 0.1, "b": 1000000, "c": 1}`, `{"a": 0.1000000001, "b": 1000000.5, "c": 1.00001}`, []error{fmt.Errorf("c mismatch. 1 vs. 1.00001")}},
+		{"numeric tolerance by path", []Option{WithNumericTolerance(0.01, 0, "prices[*]"), WithNumericTolerance(0, 0.1)}, `{"prices": [1.5, 2], "total": 100}`, `{"prices": [1.505, 2.1], "total": 109}`, []error{fmt.Errorf("prices[1] mismatch. 2 vs. 2.1")}},
+		{"underflowing number isn't empty", nil, `{"a": 1e-400, "b": 0e-400, "c": -0.0}`, `{"a": null, "c": null}`, []error{fmt.Errorf("a mismatch. 1e-400 vs. <nil>")}},
+		{"infinite tolerance", []Option{WithTolerance(math.Inf(1))}, `{"a": 1, "b": 0}`, `{"a": 1e300, "b": 0.5}`, nil},
+		{"NaN percent", []Option{WithinPercent(math.NaN())}, `{"a": 1, "b": 2}`, `{"a": 1.0, "b": 3}`, []error{fmt.Errorf("b mismatch. 2 vs. 3")}},
+		{"infinite numeric tolerance", []Option{WithNumericTolerance(math.Inf(-1), math.Inf(1))}, `{"a": 1, "b": 0}`, `{"a": 2, "b": 0.5}`, nil},
+		{"NaN rule tolerance", []Option{WithRules(map[string]Rule{"a": {Tolerance: &nan}})}, `{"a": 1}`, `{"a": 1.5}`, []error{fmt.Errorf("a mismatch. 1 vs. 1.5")}},
+		{"percent with decimals", []Option{WithinPercent(1), WithDecimal(2)}, `{"a": 100.00, "b": 100}`, `{"a": 101.00, "b": 98.99}`, []error{fmt.Errorf("b mismatch. 100 vs. 98.99")}},
+		{"ignore paths", []Option{WithIgnorePaths("meta.requestId", "items[*].updatedAt")},
+			`{"meta": {"requestId": "a", "b": 1}, "items": [{"id": 1, "updatedAt": "x"}]}`,
+			`{"meta": {"requestId": "b", "b": 1}, "items": [{"id": 1, "updatedAt": "y"}]}`, nil},
+		{"ignore paths missing or retyped", []Option{WithIgnorePaths("meta.requestId", "items[*].updatedAt"), Strict()},
+			`{"meta": {"requestId": "a"}, "items": [{"id": 1, "updatedAt": "x"}]}`,
+			`{"meta": {}, "items": [{"id": 1, "updatedAt": 1700000000}]}`, nil},
+		{"ignore wildcard key", []Option{WithIgnorePaths("meta.*")}, `{"meta": {"a": 1}, "b": 1}`, `{"meta": {"a": 2}, "b": 2}`, []error{fmt.Errorf("b mismatch. 1 vs. 2")}},
+		{"ignore is not a prefix match", []Option{WithIgnorePaths("meta")}, `{"metadata": 1}`, `{"metadata": 2}`, []error{fmt.Errorf("metadata mismatch. 1 vs. 2")}},
+		{"matcher", []Option{WithMatcher("items[*].id", MatchRegexp(`^ord-\d+$`))},
+			`{"items": [{"id": "ord-1"}, {"id": "ord-2"}]}`,
+			`{"items": [{"id": "ord-3"}, {"id": "x-4"}]}`,
+			[]error{fmt.Errorf(`items[1].id mismatch. "x-4" doesn't match regexp "^ord-\\d+$"`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestComparerEqualSlice(t *testing.T) {
+	errs := NewComparer(Strict()).EqualSlice([]byte(`[[], null]`), []byte(`[null, []]`))
+	checkErrors(t, []error{fmt.Errorf("[0] mismatch. [] vs. <nil>"), fmt.Errorf("[1] mismatch. <nil> vs. []")}, errs)
+
+	errs = NewComparer(WithIgnorePaths("[*].id")).EqualSlice([]byte(`[{"id": "a", "b": 1}]`), []byte(`[{"id": "b", "b": 2}]`))
+	checkErrors(t, []error{fmt.Errorf("[0].b mismatch. 1 vs. 2")}, errs)
+}
+
+func TestPathPattern(t *testing.T) {
+	tests := []struct {
+		path     string
+		location string
+		expected bool
+	}{
+		{"a.b", "a.b", true},
+		{"a.b", "a.bc", false},
+		{"items[*].id", "items[12].id", true},
+		{"items[*].id", "items.id", false},
+		{"*.id", "a.id", true},
+		{"*.id", "a.b.id", false},
+		{"*Amount", "paidAmount", true},
+		{"*Amount", "paidAmountCode", false},
+		{"**.id", "id", true},
+		{"**.id", "a[0].b.id", true},
+		{"**.id", "[0].id", true},
+		{"**.id", "a.paidid", false},
+		{"a.**", "a.b[1].c", true},
+		{"a.**", "ab.c", false},
+	}
+	for _, tt := range tests {
+		if actual := compilePathPattern(tt.path).match(tt.location); actual != tt.expected {
+			t.Errorf("%s matching %s: expected %t, got %t", tt.path, tt.location, tt.expected, actual)
+		}
+	}
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	SetDefaultOptions(WithIgnorePaths("a"), Strict())
+	defer SetDefaultOptions()
+	json1, json2 := []byte(`{"a": 1, "b": ""}`), []byte(`{"a": 2}`)
+
+	checkErrors(t, []error{fmt.Errorf(`b mismatch. "" vs. <nil>`)}, EqualMap(json1, json2))
+	checkErrors(t, nil, NewComparer(Preset(PresetLenient)).EqualMap(json1, json2))
+	SetDefaultOptions()
+	checkErrors(t, []error{fmt.Errorf("a mismatch. 1 vs. 2")}, EqualMap(json1, json2))
+}
+
+func TestComparerWith(t *testing.T) {
+	c := NewComparer(WithIgnorePaths("a"))
+	derived := c.With(WithIgnorePaths("b"), Strict())
+	if errs := c.EqualMap([]byte(`{"a": 1, "b": 1}`), []byte(`{"a": 2, "b": 2}`)); len(errs) != 1 {
+		t.Errorf("expected the original comparer to be unchanged, got %v", errs)
+	}
+	if errs := derived.EqualMap([]byte(`{"a": 1, "b": 1, "c": ""}`), []byte(`{"a": 2, "b": 2}`)); len(errs) != 1 {
+		t.Errorf("expected the derived comparer to ignore a and b and be strict, got %v", errs)
+	}
+}
+
+// composeAcute stands in for norm.NFC.String, composing the one character the tests use.
+func composeAcute(s string) string {
+	return strings.ReplaceAll(s, "e\u0301", "\u00e9")
+}
+
+// randomJSON returns a random JSON document built from values that the lenient rules consider
+// equal to each other in various ways, so that comparing two of them exercises those rules.
+func randomJSON(r *rand.Rand, depth int) interface{} {
+	leaves := []interface{}{nil, "", "a", "A", "1,000", json.Number("0"), json.Number("-0"), json.Number("1000"), json.Number("1e3"), json.Number("1000.4"), false, true, []interface{}{}, map[string]interface{}{}}
+	switch n := r.Intn(6); {
+	case depth > 2 || n < 3:
+		return leaves[r.Intn(len(leaves))]
+	case n == 3:
+		slice := []interface{}{}
+		for i := r.Intn(3); i > 0; i-- {
+			slice = append(slice, randomJSON(r, depth+1))
+		}
+		return slice
+	}
+	m := map[string]interface{}{}
+	for i := r.Intn(4); i > 0; i-- {
+		m[string(rune('a'+r.Intn(3)))] = randomJSON(r, depth+1)
+	}
+	return m
+}
+
+func TestSymmetry(t *testing.T) {
+	optionSets := map[string][]Option{
+		"lenient":            nil,
+		"strict":             {Strict()},
+		"strict presence":    {StrictPresence()},
+		"empty objects":      {DistinguishEmptyObjects()},
+		"deep empty objects": {DistinguishDeepEmptyObjects()},
+		"ignore paths":       {WithIgnorePaths("a", "*.b", "c[*]")},
+		"tolerance":          {WithTolerance(0.5), WithinPercent(1)},
+		"ignore case":        {WithIgnoreCase("a", "b.a")},
+		"numeric strings":    {WithNumericStrings("a", "b", "c")},
+		"decimal":            {WithDecimal(0)},
+		"negative zero":      {DistinguishNegativeZero()},
+		"lexical numbers":    {LexicalNumbers()},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		json1, _ := json.Marshal(map[string]interface{}{"a": randomJSON(r, 0), "b": randomJSON(r, 0), "c": randomJSON(r, 0)})
+		json2, _ := json.Marshal(map[string]interface{}{"a": randomJSON(r, 0), "b": randomJSON(r, 0), "c": randomJSON(r, 0)})
+		for name, opts := range optionSets {
+			c := NewComparer(opts...)
+			errs1, errs2 := c.EqualMap(json1, json2), c.EqualMap(json2, json1)
+			if len(errs1) != len(errs2) {
+				t.Fatalf("%s: %s vs. %s has %d errors %v, but the reverse has %d errors %v", name, json1, json2, len(errs1), errs1, len(errs2), errs2)
+			}
+		}
+	}
+}
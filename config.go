@@ -0,0 +1,131 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// config is the file format read by LoadConfig.
+type config struct {
+	Preset    PresetName               `json:"preset"`
+	Strict    bool                     `json:"strict"`
+	Tolerance float64                  `json:"tolerance"`
+	Percent   float64                  `json:"percent"`
+	Scale     *int                     `json:"scale"`
+	Ignore    []string                 `json:"ignore"`
+	Matchers  map[string]matcherConfig `json:"matchers"`
+	Rules     map[string]Rule          `json:"rules"`
+}
+
+type matcherConfig struct {
+	Regex string `json:"regex"`
+}
+
+// LoadConfig reads the options for a Comparer from a JSON or YAML file, so a rule set can be
+// shared between packages instead of repeating the same options in each of them. Files ending
+// in .yaml or .yml are read as YAML and any other file is read as JSON. A config file looks like:
+//   {
+//     "preset": "apicompat",
+//     "strict": true,
+//     "tolerance": 0.001,
+//     "scale": 2,
+//     "ignore": ["meta.requestId", "items[*].updatedAt"],
+//     "matchers": {"orderId": {"regex": "^ord-\\d+$"}},
+//     "rules": {"claims[*].paidAmount": {"tolerance": 0.01}}
+//   }
+// The rules key holds per-path rules in the format described on WithRules.
+// Unknown keys are reported as errors so that misspelled options don't silently do nothing.
+// YAML support covers block and flow mappings and sequences with plain or quoted scalars, which
+// is enough for config files, but not anchors, tags or multi-line strings. Numbers must be
+// finite, so a YAML value such as .inf or nan is reported as an error with its line number.
+func LoadConfig(path string) ([]Option, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(path, text)
+}
+
+// parseConfig parses text, the contents of the config file path, for LoadConfig.
+func parseConfig(path string, text []byte) ([]Option, error) {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		value, err := parseYAML(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+		}
+		if text, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+		}
+	}
+	var cfg config
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+	opts, err := cfg.options()
+	if err != nil {
+		return nil, fmt.Errorf("error in config %s: %v", path, err)
+	}
+	return opts, nil
+}
+
+func (cfg config) options() ([]Option, error) {
+	var opts []Option
+	if cfg.Preset != "" {
+		opt, err := presetOption(cfg.Preset)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	if cfg.Strict {
+		opts = append(opts, Strict())
+	}
+	if cfg.Tolerance != 0 {
+		opts = append(opts, WithTolerance(cfg.Tolerance))
+	}
+	if cfg.Percent != 0 {
+		opts = append(opts, WithinPercent(cfg.Percent))
+	}
+	if cfg.Scale != nil {
+		opts = append(opts, WithDecimal(*cfg.Scale))
+	}
+	if len(cfg.Ignore) > 0 {
+		opts = append(opts, WithIgnorePaths(cfg.Ignore...))
+	}
+	for _, path := range sortedKeys(cfg.Matchers) {
+		m := cfg.Matchers[path]
+		if m.Regex == "" {
+			return nil, fmt.Errorf("matcher for %s has no regex", path)
+		}
+		re, err := regexp.Compile(m.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("matcher for %s: %v", path, err)
+		}
+		opts = append(opts, WithMatcher(path, regexpMatcher{re}))
+	}
+	if len(cfg.Rules) > 0 {
+		rules, err := compileRules(cfg.Rules)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, withPathRules(rules))
+	}
+	return opts, nil
+}
+
+func sortedKeys(m map[string]matcherConfig) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,45 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	json1 := `{"meta": {"requestId": "a"}, "items": [{"updatedAt": "x", "price": 1.001}], "orderId": "ord-1", "note": "", "claims": [{"paidAmount": 1}]}`
+	json2 := `{"meta": {"requestId": "b"}, "items": [{"updatedAt": "y", "price": 1.002}], "orderId": "ord-x", "claims": [{"paidAmount": 1.4}]}`
+	expectedErrors := []error{
+		fmt.Errorf(`note mismatch. "" vs. <nil>`),
+		fmt.Errorf(`orderId mismatch. "ord-x" doesn't match regexp "^ord-\\d+$"`),
+	}
+	for _, filename := range []string{"testdata/config/rules.json", "testdata/config/rules.yaml", "testdata/config/flow.yaml"} {
+		t.Run(filename, func(t *testing.T) {
+			opts, err := LoadConfig(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkErrors(t, expectedErrors, NewComparer(opts...).EqualMap([]byte(json1), []byte(json2)))
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"testdata/config/unknown.json", `error parsing config testdata/config/unknown.json: json: unknown field "strikt"`},
+		{"testdata/config/badPreset.json", `error in config testdata/config/badPreset.json: unknown preset "bogus"`},
+		{"testdata/config/infTolerance.yaml", `error parsing config testdata/config/infTolerance.yaml: line 2: unsupported number "inf": must be finite`},
+		{"testdata/config/nanTolerance.yaml", `error parsing config testdata/config/nanTolerance.yaml: line 2: unsupported number "nan": must be finite`},
+		{"testdata/config/badRegex.yml", "error in config testdata/config/badRegex.yml: matcher for orderId: error parsing regexp: missing closing ): `(`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			_, err := LoadConfig(tt.filename)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("error mismatch. want: %s, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
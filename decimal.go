@@ -0,0 +1,113 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WithDecimal compares numbers as exact decimals rounded to scale decimal places, so with a
+// scale of 2, monetary amounts like 10.101 and 10.1 are equal while 12345678901234567.89 and
+// 12345678901234567.88 are not. Halves are rounded away from zero, so 10.105 and 10.11 are
+// equal. A tolerance set by WithTolerance, WithinPercent or a Rule is applied
+// to the rounded decimals. Use Rule.Scale to compare only some paths as decimals.
+func WithDecimal(scale int) Option {
+	return func(c *Comparer) {
+		c.scale, c.hasScale = scale, true
+	}
+}
+
+// WithFloat64Decimals compares numbers that aren't integers as the float64s encoding/json
+// decodes them to, as earlier versions did, rather than exactly. Digits beyond the precision of
+// a float64 are then ignored, so 0.1 and 0.10000000000000001 are equal, as are
+// 12345678901234567.89 and 12345678901234567.88, which suits fixtures written with more digits
+// than a float64 holds. Integers are still compared exactly.
+func WithFloat64Decimals() Option {
+	return func(c *Comparer) {
+		c.float64Decimals = true
+	}
+}
+
+// decimalEqual reports whether n1 and n2 are within tolerance or percent of each other once both
+// are rounded to scale decimal places.
+func decimalEqual(n1, n2 json.Number, scale int, tolerance, percent float64) bool {
+	r1, ok1 := exactNumber(n1)
+	r2, ok2 := exactNumber(n2)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return ratEqual(roundRat(r1, scale), roundRat(r2, scale), tolerance, percent)
+}
+
+// ratEqual reports whether r1 and r2 are within tolerance or percent of each other, computed
+// exactly.
+func ratEqual(r1, r2 *big.Rat, tolerance, percent float64) bool {
+	diff := new(big.Rat).Sub(r1, r2)
+	diff.Abs(diff)
+	if atMost(diff, tolerance, big.NewRat(1, 1)) {
+		return true
+	}
+	larger := new(big.Rat).Abs(r1)
+	if abs2 := new(big.Rat).Abs(r2); abs2.Cmp(larger) > 0 {
+		larger = abs2
+	}
+	return atMost(diff, percent/100, larger)
+}
+
+// atMost reports whether r is no more than f times factor, which isn't negative. An infinite or
+// NaN f, which has no exact value, follows the rules of float64 arithmetic, so NaN and zero times
+// infinity are never reached.
+func atMost(r *big.Rat, f float64, factor *big.Rat) bool {
+	switch {
+	case math.IsNaN(f):
+		return false
+	case math.IsInf(f, 0):
+		return f > 0 && factor.Sign() > 0
+	}
+	return r.Cmp(new(big.Rat).Mul(factor, exactRat(f))) <= 0
+}
+
+// exactRat returns f as the decimal it is written as, rather than its binary value, so a
+// tolerance of 0.01 is exactly one hundredth.
+func exactRat(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+// roundRat rounds r to scale decimal places, rounding halves away from zero. A negative scale
+// leaves r unrounded.
+func roundRat(r *big.Rat, scale int) *big.Rat {
+	if scale < 0 {
+		return r
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(unit))
+	quo, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if rem.Abs(rem).Lsh(rem, 1).Cmp(scaled.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(scaled.Num().Sign())))
+	}
+	return new(big.Rat).SetFrac(quo, unit)
+}
+
+var groupedNumberRegexp = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d*)?$`)
+
+// numericValue returns value as a json.Number if it is a number, or a string holding a number
+// that may be written with thousands separators.
+func numericValue(value interface{}) (json.Number, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		if groupedNumberRegexp.MatchString(s) {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+		if lenientNumberRegexp.MatchString(s) {
+			return json.Number(s), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package jsonassert
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestWithDecimal(t *testing.T) {
+	two := 2
+	cent := 0.01
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"trailing zeros", []Option{WithDecimal(4)}, `{"a": 10.10}`, `{"a": 10.1000}`, nil},
+		{"beyond float64 precision", []Option{WithDecimal(2)}, `{"a": 12345678901234567.89}`, `{"a": 12345678901234567.88}`, []error{
+			fmt.Errorf("a mismatch. 12345678901234567.89 vs. 12345678901234567.88"),
+		}},
+		{"exact without a scale", nil, `{"a": 12345678901234567.89, "b": 0.1}`, `{"a": 12345678901234567.88, "b": 0.10000000000000001}`, []error{
+			fmt.Errorf("a mismatch. 12345678901234567.89 vs. 12345678901234567.88"),
+			fmt.Errorf("b mismatch. 0.1 vs. 0.10000000000000001"),
+		}},
+		{"float64 misses the difference", []Option{WithFloat64Decimals()}, `{"a": 12345678901234567.89, "b": 0.1}`, `{"a": 12345678901234567.88, "b": 0.10000000000000001}`, nil},
+		{"float64 keeps integers exact", []Option{WithFloat64Decimals()}, `{"a": 123456789012345678}`, `{"a": 123456789012345679}`, []error{
+			fmt.Errorf("a mismatch. 123456789012345678 vs. 123456789012345679"),
+		}},
+		{"exact tolerance", []Option{WithTolerance(1)}, `{"cents": 123456789012345678, "b": 0.5}`, `{"cents": 123456789012345680, "b": 1.5}`, []error{
+			fmt.Errorf("cents mismatch. 123456789012345678 vs. 123456789012345680"),
+		}},
+		{"exact percent", []Option{WithinPercent(10)}, `{"a": 89.999999999999999999, "b": 90}`, `{"a": 100, "b": 100}`, []error{
+			fmt.Errorf("a mismatch. 89.999999999999999999 vs. 100"),
+		}},
+		{"rounded to scale", []Option{WithDecimal(2)}, `{"a": 10.105, "b": -10.105, "c": 1.004}`, `{"a": 10.11, "b": -10.11, "c": 1}`, nil},
+		{"rounded apart", []Option{WithDecimal(2)}, `{"a": 10.104}`, `{"a": 10.105}`, []error{fmt.Errorf("a mismatch. 10.104 vs. 10.105")}},
+		{"exponent", []Option{WithDecimal(0)}, `{"a": 1e3}`, `{"a": 1000}`, nil},
+		{"with tolerance", []Option{WithDecimal(2), WithTolerance(0.01)}, `{"a": 0.1, "b": 0.1}`, `{"a": 0.11, "b": 0.12}`, []error{fmt.Errorf("b mismatch. 0.1 vs. 0.12")}},
+		{"rule", []Option{WithRules(map[string]Rule{"paid": {Scale: &two, Tolerance: &cent}})}, `{"paid": 10.001, "total": 1.001}`, `{"paid": 10.014, "total": 1.004}`, []error{
+			fmt.Errorf("total mismatch. 1.001 vs. 1.004"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		value    string
+		scale    int
+		expected string
+	}{
+		{"1.005", 2, "1.01"},
+		{"-1.005", 2, "-1.01"},
+		{"1.0049", 2, "1.00"},
+		{"0.5", 0, "1"},
+		{"-0.5", 0, "-1"},
+		{"0.4", 0, "0"},
+		{"123.456", 1, "123.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			r, _ := new(big.Rat).SetString(tt.value)
+			if actual := roundRat(r, tt.scale).FloatString(tt.scale); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithNumericStrings(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"strings by default", nil, `{"a": "1,234.50"}`, `{"a": "1234.5"}`, []error{fmt.Errorf(`a mismatch. "1,234.50" vs. "1234.5"`)}},
+		{"separators", []Option{WithNumericStrings("a", "b", "c")}, `{"a": "1,234.50", "b": "-1,000,000", "c": " 12 "}`, `{"a": "1234.5", "b": -1e6, "c": 12.0}`, nil},
+		{"different numbers", []Option{WithNumericStrings("a")}, `{"a": "1,234.50"}`, `{"a": 1234.51}`, []error{fmt.Errorf(`a mismatch. "1,234.50" vs. 1234.51`)}},
+		{"bad grouping", []Option{WithNumericStrings("a")}, `{"a": "1,23"}`, `{"a": "123"}`, []error{fmt.Errorf(`a mismatch. "1,23" vs. "123"`)}},
+		{"not numbers", []Option{WithNumericStrings("a")}, `{"a": "n/a"}`, `{"a": "N/A"}`, []error{fmt.Errorf(`a mismatch. "n/a" vs. "N/A"`)}},
+		{"with tolerance", []Option{WithNumericStrings("a"), WithTolerance(0.01)}, `{"a": "1,234.50"}`, `{"a": "1234.51"}`, nil},
+		{"rule", []Option{WithRules(map[string]Rule{"rows[*].*": {NumericString: true}})}, `{"rows": [{"x": "2,000", "y": "abc"}]}`, `{"rows": [{"x": 2000, "y": "abc"}]}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
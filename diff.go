@@ -0,0 +1,227 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a diff.
+const diffContext = 3
+
+// ANSI escape sequences for the lines of a colored diff.
+const (
+	colorRemoved = "\x1b[31m"
+	colorAdded   = "\x1b[32m"
+	colorHunk    = "\x1b[36m"
+	colorReset   = "\x1b[0m"
+)
+
+// WithDiff adds a unified diff of the two documents, indented with their keys sorted, to the
+// failures reported by StructCheck, EqualT and the other assertions, after the mismatches. A
+// diff makes large failures easier to take in at a glance, but it shows every difference in
+// the text, including values the comparison considers equal, such as "" and null. With color,
+// removed lines are red and added lines green, unless the NO_COLOR environment variable is set.
+func WithDiff(color bool) Option {
+	return func(c *Comparer) {
+		c.diff = true
+		c.diffColor = color && os.Getenv("NO_COLOR") == ""
+	}
+}
+
+// WriteDiff writes a unified diff of json1 and json2 to w, after indenting them and sorting
+// their keys, so that only differences in their contents remain. It writes nothing if the
+// documents are the same.
+func WriteDiff(w io.Writer, json1, json2 []byte) error {
+	return NewComparer().WriteDiff(w, json1, json2)
+}
+
+// WriteDiff is like the package-level WriteDiff, but reads the JSON and masks values using the
+// options of the Comparer, and colors the diff if it was created with WithDiff(true).
+func (c *Comparer) WriteDiff(w io.Writer, json1, json2 []byte) error {
+	lines1, err := c.diffText(json1)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json1: %v", err)
+	}
+	lines2, err := c.diffText(json2)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json2: %v", err)
+	}
+	var sb strings.Builder
+	writeUnifiedDiff(&sb, diffLines(lines1, lines2), c.diffColor)
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// diffText returns the lines of text indented with its keys sorted and its values masked as set
+// by MaskPHI and Scrub.
+func (c *Comparer) diffText(text []byte) ([]string, error) {
+	value, err := c.getJSONValue(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(encodableNumbers(c.maskValue("", value, maskForDisplay))); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// notifyDiff logs the diff of json1 and json2 after the errors found comparing them, if set by
+// WithDiff.
+func (c *Comparer) notifyDiff(t Testing, json1, json2 []byte, errors []error) {
+	t.Helper()
+	if !c.diff || len(errors) == 0 || c.summaryOnly && !c.verbose {
+		return
+	}
+	var diff strings.Builder
+	if err := c.WriteDiff(&diff, json1, json2); err == nil && diff.Len() > 0 {
+		logf(t, "diff:\n%s", diff.String())
+	}
+}
+
+// diffLine is a line of a diff: op is ' ' for a line in both texts, '-' for a line only in the
+// first and '+' for a line only in the second.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the shortest edit script turning a into b, found with Myers' algorithm.
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[k] for k from -d-1 to d+1 before round d, for backtracking
+	var trace [][]int
+	var d int
+	for d = 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	var lines []diffLine
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			lines = append(lines, diffLine{' ', a[x]})
+		}
+		if x == prevX {
+			y--
+			lines = append(lines, diffLine{'+', b[y]})
+		} else {
+			x--
+			lines = append(lines, diffLine{'-', a[x]})
+		}
+	}
+	for x > 0 {
+		x--
+		lines = append(lines, diffLine{' ', a[x]})
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// writeUnifiedDiff writes lines as a unified diff, with the changes grouped into hunks that
+// show diffContext unchanged lines around them.
+func writeUnifiedDiff(sb *strings.Builder, lines []diffLine, color bool) {
+	// before1[i] and before2[i] count the lines of each text before lines[i]
+	before1 := make([]int, len(lines)+1)
+	before2 := make([]int, len(lines)+1)
+	for i, line := range lines {
+		before1[i+1], before2[i+1] = before1[i], before2[i]
+		if line.op != '+' {
+			before1[i+1]++
+		}
+		if line.op != '-' {
+			before2[i+1]++
+		}
+	}
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + colorReset
+	}
+	wroteHeader := false
+	for i := 0; i < len(lines); {
+		for i < len(lines) && lines[i].op == ' ' {
+			i++
+		}
+		if i == len(lines) {
+			break
+		}
+		last := i
+		for j := i; j < len(lines) && j-last <= 2*diffContext+1; j++ {
+			if lines[j].op != ' ' {
+				last = j
+			}
+		}
+		start := max(i-diffContext, 0)
+		end := min(last+diffContext+1, len(lines))
+		if !wroteHeader {
+			sb.WriteString(paint(colorRemoved, "--- json1") + "\n" + paint(colorAdded, "+++ json2") + "\n")
+			wroteHeader = true
+		}
+		sb.WriteString(paint(colorHunk, fmt.Sprintf("@@ -%s +%s @@", hunkRange(before1[start], before1[end]), hunkRange(before2[start], before2[end]))) + "\n")
+		for _, line := range lines[start:end] {
+			text := string(line.op) + line.text
+			switch line.op {
+			case '-':
+				text = paint(colorRemoved, text)
+			case '+':
+				text = paint(colorAdded, text)
+			}
+			sb.WriteString(text + "\n")
+		}
+		i = end
+	}
+}
+
+// hunkRange writes the range of lines from start to end, counted from 0, as a hunk header
+// does. An empty range is written as the line before it.
+func hunkRange(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
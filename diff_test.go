@@ -0,0 +1,135 @@
+package jsonassert
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestWriteDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		json1    string
+		json2    string
+		expected string
+	}{
+		{"numbers as written", nil, `{"b": 1, "a": [1, 2]}`, `{"a":[1,2],"b":1.0e0}`, `--- json1
++++ json2
+@@ -3,5 +3,5 @@
+     1,
+     2
+   ],
+-  "b": 1
++  "b": 1.0e0
+ }
+`},
+		{"same text", nil, `{"b": 1, "a": "<x>"}`, `{"a": "<x>", "b": 1}`, ""},
+		{"changes", nil, `{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11, "l": 12, "m": 13}`,
+			`{"b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11, "l": 12, "m": 13, "n": 14}`, `--- json1
++++ json2
+@@ -1,5 +1,4 @@
+ {
+-  "a": 1,
+   "b": 2,
+   "c": 3,
+   "d": 4,
+@@ -11,5 +10,6 @@
+   "j": 10,
+   "k": 11,
+   "l": 12,
+-  "m": 13
++  "m": 13,
++  "n": 14
+ }
+`},
+		{"masked", []Option{MaskPHI("name")}, `{"name": "Ann"}`, `{"name": "Bob"}`, ""},
+		{"colored", []Option{WithDiff(true)}, `[1]`, `[2]`, "\x1b[31m--- json1\x1b[0m\n\x1b[32m+++ json2\x1b[0m\n\x1b[36m@@ -1,3 +1,3 @@\x1b[0m\n [\n\x1b[31m-  1\x1b[0m\n\x1b[32m+  2\x1b[0m\n ]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			var sb strings.Builder
+			if err := NewComparer(tt.opts...).WriteDiff(&sb, []byte(tt.json1), []byte(tt.json2)); err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("diff mismatch. want:\n%q\ngot:\n%q", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestWriteDiffInvalid(t *testing.T) {
+	err := WriteDiff(&strings.Builder{}, []byte(`{}`), []byte(`{`))
+	if err == nil || err.Error() != "error unmarshalling json2: unexpected end of JSON input" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWithDiff(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	tester := &capableTester{}
+	RequireEqualMap(tester, []byte(`{"a": 1}`), []byte(`{"a": 2}`))
+	EqualT(tester, map[string]int{"a": 1}, map[string]int{"a": 1}, WithDiff(true))
+	EqualT(tester, map[string]int{"a": 1}, map[string]int{"a": 2}, WithDiff(true))
+	checkErrors(t, []error{
+		fmt.Errorf("*** 1 errors in json2"),
+		fmt.Errorf("a mismatch. 1 vs. 2"),
+		fmt.Errorf("*** 1 errors in actual"),
+		fmt.Errorf("a mismatch. 1 vs. 2"),
+		fmt.Errorf("diff:\n--- json1\n+++ json2\n@@ -1,3 +1,3 @@\n {\n-  \"a\": 1\n+  \"a\": 2\n }\n"),
+	}, tester.errors)
+}
+
+func TestDiffLines(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a, b := randomLines(r), randomLines(r)
+		var got1, got2 []string
+		changes := 0
+		for _, line := range diffLines(a, b) {
+			if line.op != '+' {
+				got1 = append(got1, line.text)
+			}
+			if line.op != '-' {
+				got2 = append(got2, line.text)
+			}
+			if line.op != ' ' {
+				changes++
+			}
+		}
+		if strings.Join(got1, ",") != strings.Join(a, ",") || strings.Join(got2, ",") != strings.Join(b, ",") {
+			t.Fatalf("diff of %v and %v doesn't reproduce them", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("diff of %v and %v has %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+func randomLines(r *rand.Rand) []string {
+	lines := make([]string, r.Intn(8))
+	for i := range lines {
+		lines[i] = string(rune('a' + r.Intn(3)))
+	}
+	return lines
+}
+
+func lcsLength(a, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
@@ -0,0 +1,65 @@
+package jsonassert
+
+import (
+	"iter"
+)
+
+// Differences returns an iterator over the differences between json1 and json2, found with the
+// given options in the order EqualMap and EqualSlice return them. The documents may hold any
+// JSON value. The comparison runs as the iterator is ranged over and stops when the loop does,
+// so breaking early on large, very different documents skips the rest of the comparison and
+// never holds all of the differences at once:
+//   for m := range jsonassert.Differences(json1, json2) {
+//     if strings.HasPrefix(m.Path, "claims") {
+//       return m
+//     }
+//   }
+// A document that can't be unmarshalled is reported as a Mismatch with an empty path.
+func Differences(json1, json2 []byte, opts ...Option) iter.Seq[Mismatch] {
+	return NewComparer(opts...).Differences(json1, json2)
+}
+
+// Differences is like the package-level Differences, but compares the JSON using the options of
+// the Comparer.
+func (c *Comparer) Differences(json1, json2 []byte) iter.Seq[Mismatch] {
+	return func(yield func(Mismatch) bool) {
+		streaming := c.With()
+		streaming.sink = &mismatchSink{yield: yield}
+		value1, value2, errors := streaming.unmarshalValues(json1, json2)
+		if len(errors) > 0 {
+			streaming.collect(errors, nil)
+			return
+		}
+		streaming.collect(streaming.compareValues("", value1, value2), nil)
+	}
+}
+
+// mismatchSink receives the differences found by a comparison as they are found, for
+// Differences.
+type mismatchSink struct {
+	yield   func(Mismatch) bool
+	stopped bool
+}
+
+// done reports whether the comparison should stop, because the loop ranging over the
+// differences has ended.
+func (s *mismatchSink) done() bool {
+	return s != nil && s.stopped
+}
+
+// collect appends more to errors. When the Comparer is streaming to Differences, it passes both
+// to the sink instead, so that the differences are reported in order as they are found.
+func (c *Comparer) collect(errors, more []error) []error {
+	if c.sink == nil {
+		return append(errors, more...)
+	}
+	for _, errs := range [][]error{errors, more} {
+		for _, err := range errs {
+			if c.sink.stopped {
+				return nil
+			}
+			c.sink.stopped = !c.sink.yield(asMismatch(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDifferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		json1      string
+		json2      string
+		opts       []Option
+		limit      int
+		mismatches []Mismatch
+	}{
+		{
+			name:  "equal",
+			json1: `{"a": 1, "b": ""}`,
+			json2: `{"a": 1}`,
+		},
+		{
+			name:  "nested",
+			json1: `{"a": {"b": [1, 2]}, "c": "x", "d": true}`,
+			json2: `{"a": {"b": [1, 3]}, "c": "y", "e": 1}`,
+			mismatches: []Mismatch{
+				{Path: "a.b[1]", Kind: KindValue, Code: "JA003", Expected: json.Number("2"), Actual: json.Number("3"), Message: "a.b[1] mismatch. 2 vs. 3"},
+				{Path: "c", Kind: KindValue, Code: "JA003", Expected: "x", Actual: "y", Message: `c mismatch. "x" vs. "y"`},
+				{Path: "d", Kind: KindMissing, Code: "JA001", Expected: true, Actual: nil, Message: "d mismatch. true vs. <nil>"},
+				{Path: "e", Kind: KindExtra, Code: "JA004", Expected: nil, Actual: json.Number("1"), Message: "e mismatch. <nil> vs. 1"},
+			},
+		},
+		{
+			name:  "break early",
+			json1: `[{"a": 1, "b": 1}, {"a": 1}]`,
+			json2: `[{"a": 2, "b": 2}, {"a": 2}]`,
+			limit: 2,
+			mismatches: []Mismatch{
+				{Path: "[0].a", Kind: KindValue, Code: "JA003", Expected: json.Number("1"), Actual: json.Number("2"), Message: "[0].a mismatch. 1 vs. 2"},
+				{Path: "[0].b", Kind: KindValue, Code: "JA003", Expected: json.Number("1"), Actual: json.Number("2"), Message: "[0].b mismatch. 1 vs. 2"},
+			},
+		},
+		{
+			name:  "money before its other keys",
+			json1: `{"price": {"amount": 1, "currency": "USD", "note": "a"}}`,
+			json2: `{"price": {"amount": 2, "currency": "USD", "note": "b"}}`,
+			opts:  []Option{WithMoney("price")},
+			mismatches: []Mismatch{
+				{Path: "price", Kind: KindValue, Code: "JA003", Expected: map[string]interface{}{"amount": json.Number("1"), "currency": "USD", "note": "a"}, Actual: map[string]interface{}{"amount": json.Number("2"), "currency": "USD", "note": "b"}, Message: "price mismatch. 1 USD vs. 2 USD"},
+				{Path: "price.note", Kind: KindValue, Code: "JA003", Expected: "a", Actual: "b", Message: `price.note mismatch. "a" vs. "b"`},
+			},
+		},
+		{
+			name:  "keyed arrays",
+			json1: `{"items": [{"id": 1, "n": 1}, {"id": 2}]}`,
+			json2: `{"items": [{"id": 1, "n": 2}]}`,
+			opts:  []Option{WithArrayKey("items", "id")},
+			mismatches: []Mismatch{
+				{Path: "items[0].n", Kind: KindValue, Code: "JA003", Expected: json.Number("1"), Actual: json.Number("2"), Message: "items[0].n mismatch. 1 vs. 2"},
+				{Path: "items[1]", Kind: KindMissing, Code: "JA001", Expected: map[string]interface{}{"id": json.Number("2")}, Actual: nil, Message: "items[1] mismatch. map[id:2] vs. <nil>"},
+			},
+		},
+		{
+			name:  "invalid json",
+			json1: `{`,
+			json2: `[`,
+			mismatches: []Mismatch{
+				{Kind: KindInvalid, Code: "JA007", Message: "error unmarshalling json1: unexpected end of JSON input"},
+				{Kind: KindInvalid, Code: "JA007", Message: "error unmarshalling json2: unexpected end of JSON input"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mismatches []Mismatch
+			for m := range Differences([]byte(tt.json1), []byte(tt.json2), tt.opts...) {
+				mismatches = append(mismatches, m)
+				if len(mismatches) == tt.limit {
+					break
+				}
+			}
+			if !reflect.DeepEqual(mismatches, tt.mismatches) {
+				t.Errorf("Differences() = %q, want %q", mismatches, tt.mismatches)
+			}
+			if tt.limit > 0 {
+				return
+			}
+			var errors []error
+			for _, m := range tt.mismatches {
+				errors = append(errors, m)
+			}
+			checkErrors(t, errors, NewComparer(tt.opts...).equalValues([]byte(tt.json1), []byte(tt.json2)))
+		})
+	}
+}
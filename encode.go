@@ -0,0 +1,69 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// WithEscapeHTML makes StructCheck re-encode the result with &, < and > escaped as \u0026,
+// \u003c and \u003e, as json.Marshal does. Strings are compared after decoding, so escaping only
+// changes the fixtures rewritten by JSONASSERT_UPDATE, which otherwise keep these characters as
+// they are.
+func WithEscapeHTML() Option {
+	return func(c *Comparer) {
+		c.escapeHTML = true
+	}
+}
+
+// WithIndent sets the prefix and indent that fixtures and golden files rewritten by
+// JSONASSERT_UPDATE are indented with, as json.Indent uses them. The default is an indent of two
+// spaces with no prefix.
+func WithIndent(prefix, indent string) Option {
+	return func(c *Comparer) {
+		c.indentPrefix, c.indent, c.hasIndent = prefix, indent, true
+	}
+}
+
+// WithMarshal makes StructCheck re-encode the result with marshal instead of encoding/json, for
+// types that are written by another encoder in production, such as one configured with custom
+// marshalers for third-party types:
+//   jsonassert.NewComparer(jsonassert.WithMarshal(jsoniter.ConfigCompatibleWithStandardLibrary.Marshal))
+// The result is still decoded with encoding/json, or the Codec set by WithCodec. WithEscapeHTML
+// doesn't apply to marshal, and an error returned by marshal fails the check.
+func WithMarshal(marshal func(v interface{}) ([]byte, error)) Option {
+	return func(c *Comparer) {
+		c.marshal = marshal
+	}
+}
+
+// encode re-encodes the result of StructCheck as set by WithEscapeHTML, WithMarshal and
+// WithCodec.
+func (c *Comparer) encode(result interface{}) ([]byte, error) {
+	if c.marshal != nil {
+		return c.marshal(result)
+	}
+	if c.codec != nil {
+		return c.codec.Marshal(result)
+	}
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(c.escapeHTML)
+	if err := encoder.Encode(result); err != nil {
+		return nil, err
+	}
+	return encoded.Bytes(), nil
+}
+
+// indentFixture indents the JSON in text as set by WithIndent, for the fixtures and golden files
+// rewritten by JSONASSERT_UPDATE.
+func (c *Comparer) indentFixture(text []byte) ([]byte, error) {
+	prefix, indent := "", "  "
+	if c.hasIndent {
+		prefix, indent = c.indentPrefix, c.indent
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, text, prefix, indent); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type htmlStruct struct {
+	A string `json:"a"`
+	B string `json:"b,omitempty"`
+}
+
+func TestStructCheckEncoding(t *testing.T) {
+	tests := []struct {
+		name            string
+		fixture         string
+		opts            []Option
+		expectedErrors  []error
+		expectedFixture string
+	}{
+		{"html characters", `{"a": "<b> & </b>"}`, nil, nil, ""},
+		{"escaped html characters", `{"a": "<b> & </b>"}`, []Option{WithEscapeHTML()}, nil, ""},
+		{"custom marshal", `{"a": "x", "b": "y"}`, []Option{WithMarshal(func(v interface{}) ([]byte, error) {
+			return json.Marshal(map[string]string{"a": v.(*htmlStruct).A})
+		})}, []error{
+			fmt.Errorf("*** 1 errors in FIXTURE"),
+			fmt.Errorf(`b mismatch. "y" vs. <nil>`),
+		}, ""},
+		{"marshal error", `{"a": "x"}`, []Option{WithMarshal(func(v interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("no marshaler for htmlStruct")
+		})}, []error{fmt.Errorf("error encoding json for FIXTURE: no marshaler for htmlStruct")}, ""},
+		{"update keeps html characters", `{"a": "<b>", "c": 1}`, []Option{withUpdate()}, nil, "{\n  \"a\": \"<b>\"\n}\n"},
+		{"update escapes html characters", `{"a": "<b>", "c": 1}`, []Option{withUpdate(), WithEscapeHTML()}, nil, "{\n  \"a\": \"\\u003cb\\u003e\"\n}\n"},
+		{"update with indent", `{"a": "x", "c": 1}`, []Option{withUpdate(), WithIndent("", "\t")}, nil, "{\n\t\"a\": \"x\"\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := filepath.Join(t.TempDir(), "fixture.json")
+			if err := os.WriteFile(fixture, []byte(tt.fixture), 0644); err != nil {
+				t.Fatal(err)
+			}
+			fakeT := &fakeTester{}
+			NewComparer(tt.opts...).StructCheck(fakeT, fixture, &htmlStruct{})
+			var expectedErrors []error
+			for _, err := range tt.expectedErrors {
+				expectedErrors = append(expectedErrors, fmt.Errorf("%s", strings.Replace(err.Error(), "FIXTURE", fixture, 1)))
+			}
+			checkErrors(t, expectedErrors, fakeT.errors)
+			if tt.expectedFixture == "" {
+				tt.expectedFixture = tt.fixture
+			}
+			if text, _ := os.ReadFile(fixture); string(text) != tt.expectedFixture {
+				t.Errorf("fixture = %q, want %q", text, tt.expectedFixture)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package jsonassert
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by OptionsFromEnv.
+const (
+	// EnvUpdate rewrites fixtures that StructCheck can't round trip with the JSON encoded from
+	// the result, and golden files that differ from the JSON passed to Golden, when set to a true
+	// value such as 1.
+	EnvUpdate = "JSONASSERT_UPDATE"
+	// EnvMaxErrors limits the number of mismatches StructCheck reports to the given number.
+	EnvMaxErrors = "JSONASSERT_MAX_ERRORS"
+	// EnvVerbose adds a tree of the differences to a failed StructCheck when set to a true value.
+	EnvVerbose = "JSONASSERT_VERBOSE"
+	// EnvTrace writes the outcome of comparing every path to standard error, as WithTrace does,
+	// when set to a true value.
+	EnvTrace = "JSONASSERT_TRACE"
+	// EnvSummary reports a single line for the mismatches found by each assertion, as
+	// SummaryOnly does, when set to a true value.
+	EnvSummary = "JSONASSERT_SUMMARY"
+)
+
+// OptionsFromEnv returns the options set by environment variables, so CI and local runs can
+// change how failures are reported without changing the tests:
+//   JSONASSERT_UPDATE=1       rewrite fixtures and golden files instead of failing
+//   JSONASSERT_MAX_ERRORS=50  report at most 50 mismatches for each StructCheck
+//   JSONASSERT_VERBOSE=1      report a tree of the differences when StructCheck fails
+//   JSONASSERT_TRACE=1        write the outcome of comparing every path to standard error
+//   JSONASSERT_SUMMARY=1      report a single line for the mismatches of each assertion
+// Boolean variables accept the values strconv.ParseBool does. Unset or empty variables are
+// ignored. NewComparer applies these options after its arguments, so the environment overrides
+// the options set in code.
+func OptionsFromEnv() ([]Option, error) {
+	opts, err := envOptions()
+	if err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// envOptions returns the options set by the valid environment variables, skipping the invalid
+// ones, along with an error describing each invalid variable.
+func envOptions() ([]Option, error) {
+	var opts []Option
+	var errs []error
+	if update, err := envBool(EnvUpdate); err != nil {
+		errs = append(errs, err)
+	} else if update {
+		opts = append(opts, withUpdate())
+	}
+	if s := os.Getenv(EnvMaxErrors); s != "" {
+		if n, err := strconv.Atoi(s); err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: must be a non-negative integer", EnvMaxErrors, s))
+		} else {
+			opts = append(opts, WithMaxErrors(n))
+		}
+	}
+	if verbose, err := envBool(EnvVerbose); err != nil {
+		errs = append(errs, err)
+	} else if verbose {
+		opts = append(opts, withVerbose())
+	}
+	if trace, err := envBool(EnvTrace); err != nil {
+		errs = append(errs, err)
+	} else if trace {
+		opts = append(opts, WithTrace(os.Stderr))
+	}
+	if summary, err := envBool(EnvSummary); err != nil {
+		errs = append(errs, err)
+	} else if summary {
+		opts = append(opts, SummaryOnly())
+	}
+	return opts, errors.Join(errs...)
+}
+
+func envBool(name string) (bool, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be a boolean such as 1 or 0", name, s)
+	}
+	return b, nil
+}
+
+func withUpdate() Option {
+	return func(c *Comparer) {
+		c.update = true
+	}
+}
+
+func withVerbose() Option {
+	return func(c *Comparer) {
+		c.verbose = true
+	}
+}
@@ -0,0 +1,146 @@
+package jsonassert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOptionsFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      Comparer
+		expectedError string
+	}{
+		{"unset", nil, Comparer{}, ""},
+		{"all set", map[string]string{EnvUpdate: "1", EnvMaxErrors: "50", EnvVerbose: "true"}, Comparer{update: true, maxErrors: 50, verbose: true}, ""},
+		{"false", map[string]string{EnvUpdate: "0", EnvVerbose: "false"}, Comparer{}, ""},
+		{"bad bool", map[string]string{EnvVerbose: "yes"}, Comparer{}, `invalid JSONASSERT_VERBOSE "yes": must be a boolean such as 1 or 0`},
+		{"bad number", map[string]string{EnvMaxErrors: "-1"}, Comparer{}, `invalid JSONASSERT_MAX_ERRORS "-1": must be a non-negative integer`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvUpdate, EnvMaxErrors, EnvVerbose, EnvTrace, EnvSummary} {
+				t.Setenv(name, tt.env[name])
+			}
+			opts, err := OptionsFromEnv()
+			if err == nil && tt.expectedError != "" || err != nil && err.Error() != tt.expectedError {
+				t.Fatalf("expected error %q, got %v", tt.expectedError, err)
+			}
+			var c Comparer
+			for _, opt := range opts {
+				opt(&c)
+			}
+			if c.update != tt.expected.update || c.maxErrors != tt.expected.maxErrors || c.verbose != tt.expected.verbose {
+				t.Errorf("expected %+v, got %+v", tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestOptionsFromEnvTrace(t *testing.T) {
+	t.Setenv(EnvTrace, "1")
+	opts, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Comparer
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.trace == nil {
+		t.Errorf("expected %s to set a trace", EnvTrace)
+	}
+}
+
+func TestNewComparerBadEnv(t *testing.T) {
+	t.Setenv(EnvMaxErrors, "many")
+	t.Setenv(EnvVerbose, "yes")
+	t.Setenv(EnvUpdate, "1")
+	c := NewComparer()
+	if !c.update || c.verbose || c.maxErrors != 0 {
+		t.Errorf("expected only %s to be applied, got %+v", EnvUpdate, c)
+	}
+	if errors := c.EqualMap([]byte(`{"a":1}`), []byte(`{"a":1}`)); len(errors) > 0 {
+		t.Errorf("expected no errors, got %v", errors)
+	}
+	fakeT := &fakeTester{}
+	c.RequireEqualMap(fakeT, []byte(`{"a":1}`), []byte(`{"a":1}`))
+	checkErrors(t, []error{
+		fmt.Errorf("invalid JSONASSERT_MAX_ERRORS \"many\": must be a non-negative integer\n" +
+			"invalid JSONASSERT_VERBOSE \"yes\": must be a boolean such as 1 or 0"),
+	}, fakeT.errors)
+}
+
+func TestStructCheckEnv(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            map[string]string
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"max errors", map[string]string{EnvMaxErrors: "2"}, "testdata/nearMiss.json", &nearMissStruct{}, []error{
+			fmt.Errorf("*** 3 errors in testdata/nearMiss.json"),
+			fmt.Errorf(`first_name mismatch. "a" vs. <nil>`),
+			fmt.Errorf(`memberId mismatch. "1" vs. <nil>`),
+			fmt.Errorf("… and 1 more errors"),
+			fmt.Errorf("first_name: tag `firstName` on jsonassert.nearMissStruct.FirstName vs key `first_name`"),
+			fmt.Errorf("memberId: tag `memberID` on jsonassert.nearMissStruct.MemberID vs key `memberId`"),
+		}},
+		{"verbose", map[string]string{EnvVerbose: "1"}, "testdata/nearMiss.json", &nearMissStruct{}, []error{
+			fmt.Errorf("*** 3 errors in testdata/nearMiss.json"),
+			fmt.Errorf(`first_name mismatch. "a" vs. <nil>`),
+			fmt.Errorf(`memberId mismatch. "1" vs. <nil>`),
+			fmt.Errorf(`memberID mismatch. <nil> vs. "1"`),
+			fmt.Errorf("differences in testdata/nearMiss.json:\n{\n  … (1 key equal)\n  first_name: \"a\" vs. <nil>\n  memberID: <nil> vs. \"1\"\n  memberId: \"1\" vs. <nil>\n}\n"),
+			fmt.Errorf("first_name: tag `firstName` on jsonassert.nearMissStruct.FirstName vs key `first_name`"),
+			fmt.Errorf("memberId: tag `memberID` on jsonassert.nearMissStruct.MemberID vs key `memberId`"),
+		}},
+		{"bad env", map[string]string{EnvMaxErrors: "many"}, "testdata/complete.json", &receiveStruct{}, []error{
+			fmt.Errorf(`invalid JSONASSERT_MAX_ERRORS "many": must be a non-negative integer`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			fakeT := &fakeTester{}
+			StructCheck(fakeT, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestStructCheckUpdate(t *testing.T) {
+	t.Setenv(EnvUpdate, "1")
+	filename := filepath.Join(t.TempDir(), "complete.json")
+	if err := os.WriteFile(filename, []byte(jsonComplete), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakeT := &fakeTester{}
+	StructCheck(fakeT, filename, &subStruct{})
+	checkErrors(t, nil, fakeT.errors)
+	expected := "{\n  \"a\": \"\",\n  \"b\": \"\"\n}\n"
+	if actual := getJSON(filename); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestStructCheckUpdateHTML(t *testing.T) {
+	t.Setenv(EnvUpdate, "1")
+	filename := filepath.Join(t.TempDir(), "html.json")
+	if err := os.WriteFile(filename, []byte(`{"a": "<b> & </b>", "c": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakeT := &fakeTester{}
+	StructCheck(fakeT, filename, &subStruct{})
+	checkErrors(t, nil, fakeT.errors)
+	expected := "{\n  \"a\": \"<b> & </b>\",\n  \"b\": \"\"\n}\n"
+	if actual := getJSON(filename); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
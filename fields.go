@@ -0,0 +1,192 @@
+package jsonassert
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var fieldCache sync.Map // map[reflect.Type][]structField
+
+// structField is a struct field as seen by encoding/json, including fields promoted from
+// embedded structs.
+type structField struct {
+	name      string // JSON key
+	goName    string // Go field name
+	index     []int
+	typ       reflect.Type
+	tagged    bool // name came from a json tag
+	omitEmpty bool
+	omitZero  bool
+}
+
+// typeFields returns the fields encoding/json would use for the struct type t. It follows the
+// same rules as encoding/json: unexported fields are skipped, fields tagged "-" are skipped,
+// fields of embedded structs are promoted and conflicts between promoted fields are resolved
+// by depth and then by the presence of a json tag. Fields that remain ambiguous are dropped.
+func typeFields(t reflect.Type) []structField {
+	fields := candidateFields(t)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+
+	var dominant []structField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if field, ok := dominantField(fields[i:j]); ok {
+			dominant = append(dominant, field)
+		}
+		i = j
+	}
+	sort.Slice(dominant, func(i, j int) bool { return indexLess(dominant[i].index, dominant[j].index) })
+	return dominant
+}
+
+// cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
+func cachedTypeFields(t reflect.Type) []structField {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return fields.([]structField)
+}
+
+// candidateFields returns every field encoding/json considers for t, before conflicting
+// names are resolved.
+func candidateFields(t reflect.Type) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []structField
+	current := []embedded{}
+	next := []embedded{{typ: t}}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		var level []structField
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := structField{
+						name:      name,
+						goName:    sf.Name,
+						index:     index,
+						typ:       sf.Type,
+						tagged:    name != "",
+						omitEmpty: tagHasOption(opts, "omitempty"),
+						omitZero:  tagHasOption(opts, "omitzero"),
+					}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					level = append(level, field)
+					continue
+				}
+				next = append(next, embedded{typ: ft, index: index})
+			}
+		}
+		fields = append(fields, level...)
+	}
+	return fields
+}
+
+// dominantField picks the field that wins among fields sharing the same JSON name. The
+// fields must be in the order candidateFields returned them, which is shallowest first.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	var shallowest []structField
+	for _, field := range fields {
+		if len(field.index) == depth {
+			shallowest = append(shallowest, field)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	var tagged []structField
+	for _, field := range shallowest {
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+// findField returns the field that encoding/json would decode the JSON key into. Like
+// encoding/json, an exact match is preferred and a case-insensitive match is used otherwise.
+func findField(fields []structField, key string) (structField, bool) {
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return structField{}, false
+}
+
+func parseTag(tag string) (string, string) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+func tagHasOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
@@ -0,0 +1,102 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Assertion is a chainable alternative to option slices for one-off assertions. Create one with
+// For, and finish it with Assert:
+//   jsonassert.For(t).Expect(actual).ToEqual(expected).IgnoringPaths("meta.*").WithTolerance(0.001).Assert()
+// The expected document is json1 and the actual document is json2 in the terms used by the
+// options.
+type Assertion struct {
+	t        Testing
+	actual   interface{}
+	expected interface{}
+	opts     []Option
+}
+
+// For starts an Assertion that reports to t.
+func For(t Testing) *Assertion {
+	return &Assertion{t: t}
+}
+
+// Expect sets the actual document. Like ToEqual, it accepts JSON as a []byte, string or
+// json.RawMessage, and encodes any other value to JSON.
+func (a *Assertion) Expect(actual interface{}) *Assertion {
+	a.actual = actual
+	return a
+}
+
+// ToEqual sets the expected document.
+func (a *Assertion) ToEqual(expected interface{}) *Assertion {
+	a.expected = expected
+	return a
+}
+
+// IgnoringPaths excludes the values at paths from the comparison, as WithIgnorePaths does.
+func (a *Assertion) IgnoringPaths(paths ...string) *Assertion {
+	return a.WithOptions(WithIgnorePaths(paths...))
+}
+
+// WithTolerance considers numbers equal when they differ by no more than tolerance, as the
+// WithTolerance option does.
+func (a *Assertion) WithTolerance(tolerance float64) *Assertion {
+	return a.WithOptions(WithTolerance(tolerance))
+}
+
+// WithOptions applies any other options to the comparison.
+func (a *Assertion) WithOptions(opts ...Option) *Assertion {
+	a.opts = append(a.opts, opts...)
+	return a
+}
+
+// Assert compares the documents, reports any differences to t and returns whether they are
+// equal. Documents that are JSON arrays are compared with EqualSlice and others with EqualMap.
+func (a *Assertion) Assert() bool {
+	a.t.Helper()
+	c := NewComparer(a.opts...)
+	t, stop := c.stopOnFailure(a.t)
+	defer stop()
+	expected, err := toJSON(a.expected)
+	if err != nil {
+		t.Errorf("error encoding expected: %v", err)
+		return false
+	}
+	actual, err := toJSON(a.actual)
+	if err != nil {
+		t.Errorf("error encoding actual: %v", err)
+		return false
+	}
+	var errors []error
+	if isJSONArray(expected) {
+		errors = c.EqualSlice(expected, actual)
+	} else {
+		errors = c.EqualMap(expected, actual)
+	}
+	c.notifyErrors(t, "actual", errors)
+	c.notifyDiff(t, expected, actual, errors)
+	return len(errors) == 0
+}
+
+// toJSON returns v if it is JSON text, and otherwise encodes it to JSON.
+func toJSON(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case json.RawMessage:
+		return v, nil
+	case nil:
+		return nil, fmt.Errorf("no document")
+	}
+	return json.Marshal(v)
+}
+
+// isJSONArray reports whether text holds a JSON array.
+func isJSONArray(text []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(text, " \t\r\n"), []byte("["))
+}
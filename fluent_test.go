@@ -0,0 +1,94 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAssertion(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion func(t Testing) *Assertion
+		equal     bool
+		errors    []error
+	}{
+		{
+			name: "equal",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{"a": 1}`).ToEqual([]byte(`{"a": 1.0}`))
+			},
+			equal: true,
+		},
+		{
+			name: "mismatch",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{"a": 2}`).ToEqual(`{"a": 1}`)
+			},
+			errors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf("a mismatch. 1 vs. 2"),
+			},
+		},
+		{
+			name: "ignoring paths",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{"a": 1, "meta": {"id": "x"}}`).ToEqual(`{"a": 1, "meta": {"id": "y"}}`).IgnoringPaths("meta.*")
+			},
+			equal: true,
+		},
+		{
+			name: "with tolerance",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{"a": 1.0005}`).ToEqual(`{"a": 1}`).WithTolerance(0.001)
+			},
+			equal: true,
+		},
+		{
+			name: "with options",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{"a": "X"}`).ToEqual(`{"a": "x"}`).WithOptions(WithIgnoreCase("a"))
+			},
+			equal: true,
+		},
+		{
+			name: "arrays",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(json.RawMessage(` [1, 3]`)).ToEqual(`[1, 2]`)
+			},
+			errors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf("[1] mismatch. 2 vs. 3"),
+			},
+		},
+		{
+			name: "encodes values",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(struct {
+					A int `json:"a"`
+				}{1}).ToEqual(`{"a": 1}`)
+			},
+			equal: true,
+		},
+		{
+			name: "no expected",
+			assertion: func(t Testing) *Assertion {
+				return For(t).Expect(`{}`)
+			},
+			errors: []error{fmt.Errorf("error encoding expected: no document")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tester := &fakeTester{}
+			equal := tt.assertion(tester).Assert()
+			if equal != tt.equal {
+				t.Errorf("Assert() = %v, want %v", equal, tt.equal)
+			}
+			if !reflect.DeepEqual(tester.errors, tt.errors) {
+				t.Errorf("errors = %v, want %v", tester.errors, tt.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// NumberFormat formats the numbers in error messages and trees. A number is given as it was
+// written in the JSON.
+type NumberFormat func(n json.Number) string
+
+// SourceText formats numbers as they were written in the JSON, which is the default.
+func SourceText(n json.Number) string {
+	return string(n)
+}
+
+// FixedDecimals returns a NumberFormat that writes numbers with the given number of decimal
+// places, rounding the exact decimal value of the number, so 1e6 is written as 1000000.00 with
+// 2 decimal places.
+func FixedDecimals(decimals int) NumberFormat {
+	return func(n json.Number) string {
+		r, ok := exactNumber(n)
+		if !ok {
+			return string(n)
+		}
+		return r.FloatString(decimals)
+	}
+}
+
+// WithNumberFormat formats the numbers in error messages and trees with format, so failure
+// output can match the way amounts are written in a fixture.
+func WithNumberFormat(format NumberFormat) Option {
+	return func(c *Comparer) {
+		c.numberFormat = format
+	}
+}
+
+// WithJSONExcerpts shows the objects and arrays in mismatches as indented JSON blocks, in place
+// of the Go syntax used by default, such as map[a:val b:val2], which is ambiguous and can't be
+// pasted back into a fixture:
+//   items mismatch.
+//   json1:
+//   [
+//     {
+//       "id": 1
+//     }
+//   ]
+//   json2:
+//   null
+// Values are masked and their numbers formatted as in other messages.
+func WithJSONExcerpts() Option {
+	return func(c *Comparer) {
+		c.jsonExcerpts = true
+	}
+}
+
+// excerpt writes v, the value at location, as indented JSON for WithJSONExcerpts.
+func (c *Comparer) excerpt(location string, v interface{}) string {
+	v = c.maskValue(location, v, maskForDisplay)
+	if c.numberFormat != nil {
+		v = formatNumbers(v, c.numberFormat)
+	}
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(encodableNumbers(v)); err != nil {
+		return quoteString(v)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// encodableNumbers returns a copy of v with the numbers that aren't valid JSON, such as masked
+// numbers and NaN, replaced with strings, so that v can be encoded.
+func encodableNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if !json.Valid([]byte(v)) {
+			return string(v)
+		}
+	case map[string]interface{}:
+		encodable := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			encodable[key] = encodableNumbers(value)
+		}
+		return encodable
+	case []interface{}:
+		encodable := make([]interface{}, len(v))
+		for i, value := range v {
+			encodable[i] = encodableNumbers(value)
+		}
+		return encodable
+	}
+	return v
+}
+
+// isContainer reports whether v is an object or array.
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// quoteValue is like quoteString, but masks the value at location and those nested in it as set
+// by MaskPHI, and formats numbers, including those nested in objects and arrays, with the
+// NumberFormat of c.
+func (c *Comparer) quoteValue(location string, v interface{}) string {
+	v = c.maskValue(location, v, maskForDisplay)
+	if c.numberFormat != nil {
+		v = formatNumbers(v, c.numberFormat)
+	}
+	return quoteString(v)
+}
+
+func formatNumbers(v interface{}, format NumberFormat) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		return json.Number(format(v))
+	case map[string]interface{}:
+		formatted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			formatted[key] = formatNumbers(value, format)
+		}
+		return formatted
+	case []interface{}:
+		formatted := make([]interface{}, len(v))
+		for i, value := range v {
+			formatted[i] = formatNumbers(value, format)
+		}
+		return formatted
+	}
+	return v
+}
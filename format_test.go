@@ -0,0 +1,72 @@
+package jsonassert
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWithNumberFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"source text by default", nil, `{"a": 1e6, "b": 0.10}`, `{"a": 1000001, "b": 0.2}`, []error{
+			fmt.Errorf("a mismatch. 1e6 vs. 1000001"),
+			fmt.Errorf("b mismatch. 0.10 vs. 0.2"),
+		}},
+		{"source text", []Option{WithNumberFormat(SourceText)}, `{"a": 1e6}`, `{"a": 1000001}`, []error{fmt.Errorf("a mismatch. 1e6 vs. 1000001")}},
+		{"fixed decimals", []Option{WithNumberFormat(FixedDecimals(2))}, `{"a": 1e6, "b": 0.125}`, `{"a": 1000001, "b": 0.1}`, []error{
+			fmt.Errorf("a mismatch. 1000000.00 vs. 1000001.00"),
+			fmt.Errorf("b mismatch. 0.13 vs. 0.10"),
+		}},
+		{"nested", []Option{WithNumberFormat(FixedDecimals(1))}, `{"a": [1, {"b": 2}]}`, `{"a": [1]}`, []error{fmt.Errorf("a mismatch. [1.0 map[b:2.0]] vs. [1.0]")}},
+		{"matcher", []Option{WithNumberFormat(FixedDecimals(1)), WithMatcher("a", MatchRegexp("^x"))}, `{"a": 1}`, `{}`, []error{fmt.Errorf(`a mismatch. 1.0 doesn't match regexp "^x"`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithNumberFormatTree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewComparer(WithNumberFormat(FixedDecimals(2))).WriteTree(&buf, []byte(`{"a": 1}`), []byte(`{"a": 2.5}`)); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\n  a: 1.00 vs. 2.50\n}\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWithJSONExcerpts(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"object vs. string", nil, `{"a": {"b": "<val>", "c": [1, 2]}}`, `{"a": "none"}`, []error{
+			fmt.Errorf("a mismatch.\njson1:\n{\n  \"b\": \"<val>\",\n  \"c\": [\n    1,\n    2\n  ]\n}\njson2:\n\"none\""),
+		}},
+		{"array lengths", nil, `{"a": [1]}`, `{"a": [1, 2.50]}`, []error{
+			fmt.Errorf("a mismatch.\njson1:\n[\n  1\n]\njson2:\n[\n  1,\n  2.50\n]"),
+		}},
+		{"scalars unchanged", nil, `{"a": 1}`, `{"a": 2}`, []error{fmt.Errorf("a mismatch. 1 vs. 2")}},
+		{"masked and formatted", []Option{MaskPHI("a.ssn"), WithNumberFormat(FixedDecimals(1))}, `{"a": {"ssn": 123456789, "n": 1}}`, `{"a": "x"}`, []error{
+			fmt.Errorf("a mismatch.\njson1:\n{\n  \"n\": 1.0,\n  \"ssn\": \"*********\"\n}\njson2:\n\"x\""),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(append(tt.opts, WithJSONExcerpts())...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
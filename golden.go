@@ -0,0 +1,92 @@
+package jsonassert
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Golden compares actual, which may hold any JSON value, with the JSON stored in the golden file
+// at goldenPath, reporting any differences to t as StructCheck does. When updating, it instead
+// writes actual to the golden file, indented, if the file is missing or differs, creating its
+// directory as needed. Golden updates when JSONASSERT_UPDATE is set, or when the test binary
+// defines an "update" flag and it is set, so the usual workflow takes one line:
+//   var _ = flag.Bool("update", false, "rewrite golden files")
+//
+//   jsonassert.Golden(t, "testdata/claims.golden.json", body)
+// and go test -update rewrites the golden files. The flag is looked up rather than defined by
+// the package, so that it doesn't clash with test packages that already define it. Options for
+// a single golden file can be kept next to it in a sidecar rules file, as for StructCheck.
+func Golden(t Testing, goldenPath string, actual []byte) {
+	t.Helper()
+	NewComparer().Golden(t, goldenPath, actual)
+}
+
+// Golden is like the package-level Golden, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) Golden(t Testing, goldenPath string, actual []byte) {
+	t.Helper()
+	c.golden(t, goldenPath, actual, false)
+}
+
+// golden compares actual with the golden file at goldenPath, or updates it, as Golden does. A
+// missing golden file is created whether or not updating if createMissing is set.
+func (c *Comparer) golden(t Testing, goldenPath string, actual []byte, createMissing bool) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	sidecar, err := sidecarOptions(goldenPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c = c.With(sidecar...)
+	c.file = goldenPath
+	update := c.update || updateFlag()
+
+	golden, err := c.readFile(goldenPath)
+	if os.IsNotExist(err) && (update || createMissing) {
+		if err := c.writeGolden(goldenPath, actual); err != nil {
+			t.Error(err)
+		}
+		return
+	}
+	if os.IsNotExist(err) {
+		t.Errorf("golden file %s doesn't exist; set %s=1 or run go test -update to create it", goldenPath, EnvUpdate)
+		return
+	}
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	errors := c.equalValues(golden, actual)
+	if update && len(errors) > 0 {
+		if err := c.writeGolden(goldenPath, actual); err != nil {
+			t.Error(err)
+		}
+		return
+	}
+	c.notifyErrors(t, goldenPath, errors)
+	c.notifyDiff(t, golden, actual, errors)
+}
+
+// writeGolden writes the JSON in text to goldenPath, indented as set by WithIndent, creating its
+// directory if needed.
+func (c *Comparer) writeGolden(goldenPath string, text []byte) error {
+	if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+		return fmt.Errorf("error updating %s: %v", goldenPath, err)
+	}
+	return c.updateFixture(goldenPath, text)
+}
+
+// updateFlag returns whether the test binary defines an "update" flag and it is set.
+func updateFlag() bool {
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	update, _ := strconv.ParseBool(f.Value.String())
+	return update
+}
@@ -0,0 +1,84 @@
+package jsonassert
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var _ = flag.Bool("update", false, "rewrite golden files")
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name           string
+		golden         string
+		actual         string
+		update         bool
+		expectedErrors []error
+		expectedGolden string
+	}{
+		{"equal", `{"a": 1, "b": [true]}`, `{"b":[true],"a":1.0}`, false, nil, `{"a": 1, "b": [true]}`},
+		{"mismatch", `{"a": 1}`, `{"a": 2}`, false, []error{
+			fmt.Errorf("*** 1 errors in GOLDEN"),
+			fmt.Errorf("a mismatch. 1 vs. 2"),
+		}, `{"a": 1}`},
+		{"missing", "", `{"a": 1}`, false, []error{
+			fmt.Errorf("golden file GOLDEN doesn't exist; set JSONASSERT_UPDATE=1 or run go test -update to create it"),
+		}, ""},
+		{"update mismatch", `{"a": 1}`, `{"a":2}`, true, nil, "{\n  \"a\": 2\n}"},
+		{"update equal keeps the file", `{"a": 1}`, `{"a":1.0}`, true, nil, `{"a": 1}`},
+		{"update missing", "", `[1,2]`, true, nil, "[\n  1,\n  2\n]"},
+		{"scalar", `"x"`, `"y"`, false, []error{
+			fmt.Errorf("*** 1 errors in GOLDEN"),
+			fmt.Errorf(` mismatch. "x" vs. "y"`),
+		}, `"x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenPath := filepath.Join(t.TempDir(), "nested", "golden.json")
+			if tt.golden != "" {
+				if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenPath, []byte(tt.golden), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var opts []Option
+			if tt.update {
+				opts = append(opts, withUpdate())
+			}
+			fakeT := &fakeTester{}
+			NewComparer(opts...).Golden(fakeT, goldenPath, []byte(tt.actual))
+			var expectedErrors []error
+			for _, err := range tt.expectedErrors {
+				expectedErrors = append(expectedErrors, fmt.Errorf("%s", strings.Replace(err.Error(), "GOLDEN", goldenPath, 1)))
+			}
+			checkErrors(t, expectedErrors, fakeT.errors)
+			text, err := os.ReadFile(goldenPath)
+			if err != nil && tt.expectedGolden != "" {
+				t.Fatal(err)
+			}
+			if string(text) != tt.expectedGolden {
+				t.Errorf("golden file = %q, want %q", text, tt.expectedGolden)
+			}
+		})
+	}
+}
+
+func TestGoldenUpdateFlag(t *testing.T) {
+	goldenPath := filepath.Join(t.TempDir(), "golden.json")
+	if err := flag.Set("update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer flag.Set("update", "false")
+	fakeT := &fakeTester{}
+	Golden(fakeT, goldenPath, []byte(`{"a":1}`))
+	checkErrors(t, nil, fakeT.errors)
+	if text, err := os.ReadFile(goldenPath); err != nil || string(text) != "{\n  \"a\": 1\n}" {
+		t.Errorf("golden file = %q, %v", text, err)
+	}
+}
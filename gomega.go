@@ -0,0 +1,76 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SemanticJSONMatcher is a Gomega matcher that compares JSON as EqualMap does. It implements
+// Gomega's types.GomegaMatcher interface without importing Gomega, so it can be passed to
+// Expect(...).To() without adding a dependency to programs that don't use Gomega.
+type SemanticJSONMatcher struct {
+	expected interface{}
+	c        *Comparer
+	errors   []error
+}
+
+// MatchJSONSemantically returns a Gomega matcher that succeeds if the actual JSON equals expected
+// under the comparison and options of a Comparer, so unlike Gomega's MatchJSON, "" and null are
+// equal, and options such as WithIgnorePaths and WithTolerance apply:
+//   Expect(body).To(jsonassert.MatchJSONSemantically(expected, jsonassert.WithIgnorePaths("meta.*")))
+// Like Expect in the fluent API, it accepts JSON as a []byte, string or json.RawMessage, and
+// encodes any other value to JSON. The documents may hold any JSON value.
+func MatchJSONSemantically(expected interface{}, opts ...Option) *SemanticJSONMatcher {
+	return &SemanticJSONMatcher{expected: expected, c: NewComparer(opts...)}
+}
+
+// Match compares actual with the expected JSON. It returns an error, rather than failing, if
+// either document can't be encoded or isn't valid JSON, as Gomega's MatchJSON does.
+func (m *SemanticJSONMatcher) Match(actual interface{}) (bool, error) {
+	expectedJSON, err := toJSON(m.expected)
+	if err != nil {
+		return false, fmt.Errorf("error encoding expected: %v", err)
+	}
+	actualJSON, err := toJSON(actual)
+	if err != nil {
+		return false, fmt.Errorf("error encoding actual: %v", err)
+	}
+	m.errors = m.c.equalValues(expectedJSON, actualJSON)
+	for _, err := range m.errors {
+		if asMismatch(err).Kind == KindInvalid {
+			return false, err
+		}
+	}
+	return len(m.errors) == 0, nil
+}
+
+// FailureMessage describes the mismatches found by Match. Values are masked as set by MaskPHI
+// and Scrub.
+func (m *SemanticJSONMatcher) FailureMessage(actual interface{}) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Expected\n    %s\nto match JSON semantically\n    %s\nbut found %s:",
+		m.describe(actual), m.describe(m.expected), plural(len(m.errors), "mismatch"))
+	for _, err := range m.errors {
+		fmt.Fprintf(&sb, "\n    %v", err)
+	}
+	return sb.String()
+}
+
+// NegatedFailureMessage reports that the documents are equal.
+func (m *SemanticJSONMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n    %s\nnot to match JSON semantically\n    %s", m.describe(actual), m.describe(m.expected))
+}
+
+// describe returns v as indented JSON for a failure message, with its values masked as set by
+// MaskPHI and Scrub.
+func (m *SemanticJSONMatcher) describe(v interface{}) string {
+	text, err := toJSON(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	lines, err := m.c.diffText(text)
+	if err != nil {
+		return string(text)
+	}
+	return strings.Join(lines, "\n    ")
+}
@@ -0,0 +1,73 @@
+package jsonassert
+
+import (
+	"testing"
+)
+
+// gomegaMatcher is Gomega's types.GomegaMatcher.
+type gomegaMatcher interface {
+	Match(actual interface{}) (success bool, err error)
+	FailureMessage(actual interface{}) (message string)
+	NegatedFailureMessage(actual interface{}) (message string)
+}
+
+var _ gomegaMatcher = MatchJSONSemantically(nil)
+
+func TestMatchJSONSemantically(t *testing.T) {
+	tests := []struct {
+		name            string
+		expected        interface{}
+		actual          interface{}
+		opts            []Option
+		expectedMatch   bool
+		expectedError   string
+		expectedMessage string
+	}{
+		{"equal", `{"a": 1, "b": ""}`, []byte(`{"a":1.0}`), nil, true, "", ""},
+		{"values", map[string]int{"a": 1}, struct {
+			A int `json:"a"`
+		}{1}, nil, true, "", ""},
+		{"options", `{"a": 1, "id": 1}`, `{"a":1,"id":2}`, []Option{WithIgnorePaths("id")}, true, "", ""},
+		{"mismatch", `{"a": 1, "b": [1]}`, `{"a":2,"b":[1]}`, nil, false, "",
+			"Expected\n    {\n      \"a\": 2,\n      \"b\": [\n        1\n      ]\n    }\nto match JSON semantically\n    {\n      \"a\": 1,\n      \"b\": [\n        1\n      ]\n    }\nbut found 1 mismatch:\n    a mismatch. 1 vs. 2"},
+		{"masked", `{"ssn": "123"}`, `{"ssn":"456"}`, []Option{MaskPHI("ssn")}, false, "",
+			"Expected\n    {\n      \"ssn\": \"***\"\n    }\nto match JSON semantically\n    {\n      \"ssn\": \"***\"\n    }\nbut found 1 mismatch:\n    ssn mismatch. \"***\" vs. \"***\""},
+		{"scalars", `1`, 2, nil, false, "", "Expected\n    2\nto match JSON semantically\n    1\nbut found 1 mismatch:\n     mismatch. 1 vs. 2"},
+		{"invalid actual", `{}`, `{`, nil, false, "error unmarshalling json2: unexpected end of JSON input", ""},
+		{"unencodable", `{}`, func() {}, nil, false, "error encoding actual: json: unsupported type: func()", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MatchJSONSemantically(tt.expected, tt.opts...)
+			match, err := m.Match(tt.actual)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("Match() error = %v, want %s", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if match != tt.expectedMatch {
+				t.Fatalf("Match() = %v, want %v", match, tt.expectedMatch)
+			}
+			if !match {
+				if message := m.FailureMessage(tt.actual); message != tt.expectedMessage {
+					t.Errorf("FailureMessage() = %q, want %q", message, tt.expectedMessage)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchJSONSemanticallyNegated(t *testing.T) {
+	m := MatchJSONSemantically(`{"a": 1}`)
+	if match, err := m.Match(`{"a":1}`); !match || err != nil {
+		t.Fatalf("Match() = %v, %v", match, err)
+	}
+	expected := "Expected\n    {\n      \"a\": 1\n    }\nnot to match JSON semantically\n    {\n      \"a\": 1\n    }"
+	if message := m.NegatedFailureMessage(`{"a":1}`); message != expected {
+		t.Errorf("NegatedFailureMessage() = %q, want %q", message, expected)
+	}
+}
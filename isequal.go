@@ -0,0 +1,49 @@
+package jsonassert
+
+// IsEqualMap reports whether json1 and json2 are equal as EqualMap compares them. It stops at the
+// first difference instead of collecting them all, for hot paths such as fuzz targets, property
+// tests and benchmark gates, where only the answer matters:
+//   f.Fuzz(func(t *testing.T, data []byte) {
+//     if !jsonassert.IsEqualMap(data, roundTrip(data)) {
+//       t.Fatalf("round trip changed %s", data)
+//     }
+//   })
+// Documents that can't be unmarshalled aren't equal.
+func IsEqualMap(json1, json2 []byte) bool {
+	return NewComparer().IsEqualMap(json1, json2)
+}
+
+// IsEqualMap is like the package-level IsEqualMap, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) IsEqualMap(json1, json2 []byte) bool {
+	return c.isEqual(func(stopping *Comparer) []error {
+		return stopping.EqualMap(json1, json2)
+	})
+}
+
+// IsEqualSlice is like IsEqualMap, but compares the documents as EqualSlice does.
+func IsEqualSlice(json1, json2 []byte) bool {
+	return NewComparer().IsEqualSlice(json1, json2)
+}
+
+// IsEqualSlice is like the package-level IsEqualSlice, but compares the JSON using the options
+// of the Comparer.
+func (c *Comparer) IsEqualSlice(json1, json2 []byte) bool {
+	return c.isEqual(func(stopping *Comparer) []error {
+		return stopping.EqualSlice(json1, json2)
+	})
+}
+
+// isEqual runs compare with a copy of c that stops at the first difference, and reports whether
+// it found none. Differences found by the comparison go to the copy's sink, but the errors for
+// documents that can't be unmarshalled are returned.
+func (c *Comparer) isEqual(compare func(stopping *Comparer) []error) bool {
+	found := false
+	stopping := c.With()
+	stopping.sink = &mismatchSink{yield: func(Mismatch) bool {
+		found = true
+		return false
+	}}
+	errors := compare(stopping)
+	return !found && len(errors) == 0
+}
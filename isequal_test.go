@@ -0,0 +1,52 @@
+package jsonassert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		json1    string
+		json2    string
+		isSlice  bool
+		expected bool
+	}{
+		{"equal", nil, `{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1.0}`, false, true},
+		{"lenient", nil, `{"a": "", "b": null}`, `{}`, false, true},
+		{"different", nil, `{"a": 1, "b": [1, 2]}`, `{"a": 1, "b": [1, 3]}`, false, false},
+		{"missing key", nil, `{"a": 1}`, `{"a": 1, "b": 2}`, false, false},
+		{"options", []Option{WithIgnorePaths("b")}, `{"a": 1, "b": 2}`, `{"a": 1, "b": 3}`, false, true},
+		{"invalid", nil, `{"a": 1`, `{"a": 1}`, false, false},
+		{"slice equal", nil, `[{"a": 1}, 2]`, `[{"a": 1.0}, 2]`, true, true},
+		{"slice different", nil, `[1, 2, 3]`, `[1, 2, 4]`, true, false},
+		{"slice invalid", nil, `[1, 2]`, `[1, 2`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComparer(tt.opts...)
+			var equal bool
+			if tt.isSlice {
+				equal = c.IsEqualSlice([]byte(tt.json1), []byte(tt.json2))
+			} else {
+				equal = c.IsEqualMap([]byte(tt.json1), []byte(tt.json2))
+			}
+			if equal != tt.expected {
+				t.Errorf("IsEqual = %v, want %v", equal, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsEqualStopsAtFirstDifference(t *testing.T) {
+	var trace strings.Builder
+	json1, json2 := `[1, 2, 3, 4]`, `[5, 6, 7, 8]`
+	if NewComparer(WithTrace(&trace)).IsEqualSlice([]byte(json1), []byte(json2)) {
+		t.Fatalf("IsEqualSlice(%s, %s) = true", json1, json2)
+	}
+	if lines := strings.Count(trace.String(), "\n"); lines != 1 {
+		t.Errorf("compared %d values, want 1:\n%s", lines, trace.String())
+	}
+}
@@ -0,0 +1,98 @@
+package jsonassert
+
+import "bytes"
+
+// AllowJSONC accepts documents written as JSONC, the JSON with comments read by editors such as
+// VS Code: // line comments, /* block comments */ and a trailing comma after the last element of
+// an array or object. Hand-written expected files and golden files can then explain their
+// values in place:
+//   {
+//     "status": "denied", // CO-45, since the member's coverage ended
+//     "paidAmount": 0,
+//   }
+// Comments and trailing commas are removed before the documents are decoded, by StructCheck as
+// well as the comparisons, and error offsets still refer to the documents as written. Fixtures
+// rewritten by JSONASSERT_UPDATE lose their comments.
+func AllowJSONC() Option {
+	return func(c *Comparer) {
+		c.allowJSONC = true
+	}
+}
+
+// stripJSONC returns text with its comments and trailing commas replaced by spaces if the
+// Comparer allows JSONC, and otherwise returns text unchanged.
+func (c *Comparer) stripJSONC(text []byte) []byte {
+	if !c.allowJSONC {
+		return text
+	}
+	return removeTrailingCommas(removeComments(text))
+}
+
+// removeComments returns a copy of text with the comments outside of strings replaced by
+// spaces, keeping line breaks so that offsets and line numbers are unchanged. An unterminated
+// block comment is left as it is, for the decoder to report.
+func removeComments(text []byte) []byte {
+	out := append([]byte(nil), text...)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		ch := out[i]
+		switch {
+		case inString:
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '*':
+			n := bytes.Index(out[i+2:], []byte("*/"))
+			if n < 0 {
+				return out
+			}
+			for end := i + 2 + n + 2; i < end; i++ {
+				if out[i] != '\n' && out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return out
+}
+
+// removeTrailingCommas replaces the commas outside of strings in text that are followed only by
+// whitespace before the end of an array or object with spaces, in place.
+func removeTrailingCommas(text []byte) []byte {
+	inString := false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case inString:
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == ',':
+			next := i + 1
+			for next < len(text) && isSpace(text[next]) {
+				next++
+			}
+			if next < len(text) && (text[next] == ']' || text[next] == '}') {
+				text[i] = ' '
+			}
+		}
+	}
+	return text
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n'
+}
@@ -0,0 +1,52 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAllowJSONC(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparer       *Comparer
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"line comments", NewComparer(AllowJSONC()), "{\n  \"a\": 1, // one\n  // b is omitted\n  \"c\": 2\n}", `{"a":1,"c":2}`, nil},
+		{"block comments", NewComparer(AllowJSONC()), `{/* a */ "a": /* one */ 1}`, `{"a":1}`, nil},
+		{"trailing commas", NewComparer(AllowJSONC()), `{"a": [1, 2, ], "b": {"c": 3,},}`, `{"a":[1,2],"b":{"c":3}}`, nil},
+		{"comment markers in strings", NewComparer(AllowJSONC()), `{"url": "http://x/*y*/", "s": "a,]\"//"}`, `{"url":"http://x/*y*/","s":"a,]\"//"}`, nil},
+		{"trailing comma before a comment", NewComparer(AllowJSONC()), "[1, // last\n]", `[1]`, nil},
+		{"mismatch", NewComparer(AllowJSONC()), `{"a": 1 /* was 2 */}`, `{"a":2}`, []error{
+			fmt.Errorf("a mismatch. 1 vs. 2"),
+		}},
+		{"unterminated block comment", NewComparer(AllowJSONC()), `{"a": 1 /* }`, `{"a":1}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character '/' after object key:value pair"),
+		}},
+		{"duplicate keys", NewComparer(AllowJSONC(), RejectDuplicateKeys()), `{/* "a": 0, */ "a": 1, "a": 2}`, `{"a":2}`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate key a"),
+		}},
+		{"not allowed", NewComparer(), `{"a": 1, // one
+}`, `{"a":1}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character '/' looking for beginning of object key string"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrors(t, tt.expectedErrors, tt.comparer.equalValues([]byte(tt.json1), []byte(tt.json2)))
+		})
+	}
+}
+
+func TestAllowJSONCStructCheck(t *testing.T) {
+	type claim struct {
+		ID         string   `json:"id"`
+		Status     string   `json:"status"`
+		PaidAmount float64  `json:"paidAmount"`
+		Codes      []string `json:"codes"`
+	}
+	fakeT := &fakeTester{}
+	NewComparer(AllowJSONC()).StructCheck(fakeT, "testdata/jsonc/claim.jsonc", &claim{})
+	checkErrors(t, nil, fakeT.errors)
+}
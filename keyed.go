@@ -0,0 +1,109 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithArrayKey compares the arrays of objects at path by pairing up their elements by the value
+// of key, such as an "id", instead of by index, so a reordered array is equal and a difference
+// is reported for the element that changed, as in "items[1].price mismatch. 10 vs. 12", rather
+// than for every element after one that was added or removed. An element whose key isn't in the
+// other array is reported as a whole. Keys must be strings, numbers or booleans, and a number
+// pairs up with the string holding it; arrays with an element that lacks the key or shares it
+// with another element are compared by index. Path uses the notation described on
+// WithIgnorePaths.
+func WithArrayKey(path, key string) Option {
+	rule := newPathRule(path)
+	rule.arrayKey = key
+	return withPathRules([]pathRule{rule})
+}
+
+// slicePair is a pair of array elements to compare. An element that has no counterpart in the
+// other array is paired with nil. The index is that of the element in json1, or in json2 for
+// elements only in json2, written like "[0]".
+type slicePair struct {
+	index          string
+	value1, value2 interface{}
+}
+
+// keyedPairs pairs the elements of two arrays of objects by the value of key, so that elements
+// are compared with the element that has the same key instead of the one at the same index. It
+// returns false if key is empty, either value isn't an array, or an element isn't an object
+// with a unique value for key, in which case the arrays should be compared by index.
+func keyedPairs(value1, value2 interface{}, key string) ([]slicePair, bool) {
+	slice1, ok1 := value1.([]interface{})
+	slice2, ok2 := value2.([]interface{})
+	if key == "" || !ok1 || !ok2 {
+		return nil, false
+	}
+	index1, ok1 := indexByKey(slice1, key)
+	index2, ok2 := indexByKey(slice2, key)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	pairs := make([]slicePair, 0, len(slice1))
+	for i, element := range slice1 {
+		pair := slicePair{index: fmt.Sprintf("[%d]", i), value1: element}
+		if j, ok := index2[elementKey(element, key)]; ok {
+			pair.value2 = slice2[j]
+		}
+		pairs = append(pairs, pair)
+	}
+	for j, element := range slice2 {
+		if _, ok := index1[elementKey(element, key)]; !ok {
+			pairs = append(pairs, slicePair{index: fmt.Sprintf("[%d]", j), value2: element})
+		}
+	}
+	return pairs, true
+}
+
+// indexByKey maps the value of key in each element of slice to the index of the element.
+func indexByKey(slice []interface{}, key string) (map[string]int, bool) {
+	index := make(map[string]int, len(slice))
+	for i, element := range slice {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		switch object[key].(type) {
+		case string, bool, json.Number:
+		default:
+			return nil, false
+		}
+		k := elementKey(element, key)
+		if _, ok := index[k]; ok {
+			return nil, false
+		}
+		index[k] = i
+	}
+	return index, true
+}
+
+// elementKey writes the value of key in element, so that "1" and 1 pair up.
+func elementKey(element interface{}, key string) string {
+	return fmt.Sprint(element.(map[string]interface{})[key])
+}
+
+// compareKeyedSlices compares the elements paired by keyedPairs. An element with no counterpart
+// is reported as a whole.
+func (c *Comparer) compareKeyedSlices(location string, pairs []slicePair) []error {
+	var errors []error
+	for _, pair := range pairs {
+		indexLocation := location + pair.index
+		if pair.value1 == nil || pair.value2 == nil {
+			c.countValue(false)
+			pairErrors := []error{c.notifyError(indexLocation, pair.value1, pair.value2)}
+			if c.trace != nil {
+				c.traceValue(indexLocation, pair.value1, pair.value2, pathRule{}, "", pairErrors)
+			}
+			errors = c.collect(errors, pairErrors)
+		} else {
+			errors = c.collect(errors, c.compareValues(indexLocation, pair.value1, pair.value2))
+		}
+		if c.sink.done() {
+			break
+		}
+	}
+	return errors
+}
@@ -0,0 +1,65 @@
+package jsonassert
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestKeyedPairs(t *testing.T) {
+	tests := []struct {
+		name           string
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"reordered", `{"items": [{"id": 1, "a": "x"}, {"id": "2", "a": "y"}]}`, `{"items": [{"id": 2, "a": "y"}, {"id": 1, "a": "x"}]}`, []error{
+			fmt.Errorf(`items[1].id mismatch. "2" vs. 2`),
+		}},
+		{"missing and extra", `{"items": [{"id": 1}, {"id": 2}]}`, `{"items": [{"id": 3}, {"id": 1}]}`, []error{
+			fmt.Errorf("items[1] mismatch. map[id:2] vs. <nil>"),
+			fmt.Errorf("items[0] mismatch. <nil> vs. map[id:3]"),
+		}},
+		{"duplicate keys compared by index", `{"items": [{"id": 1}, {"id": 1}]}`, `{"items": [{"id": 1}, {"id": 2}]}`, []error{
+			fmt.Errorf("items[1].id mismatch. 1 vs. 2"),
+		}},
+		{"missing keys compared by index", `{"items": [{"id": 1}, {"x": 1}]}`, `{"items": [{"x": 1}, {"id": 1}]}`, []error{
+			fmt.Errorf("items[0].id mismatch. 1 vs. <nil>"),
+			fmt.Errorf("items[0].x mismatch. <nil> vs. 1"),
+			fmt.Errorf("items[1].x mismatch. 1 vs. <nil>"),
+			fmt.Errorf("items[1].id mismatch. <nil> vs. 1"),
+		}},
+		{"not arrays", `{"items": {"id": 1}}`, `{"items": [{"id": 1}]}`, []error{
+			fmt.Errorf("items mismatch. map[id:1] vs. [map[id:1]]"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(WithArrayKey("items", "id")).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWriteTreeKeyed(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewComparer(WithArrayKey("items", "id"))
+	if err := c.WriteTree(&buf, []byte(`{"items": [{"id": 1, "a": 1}, {"id": 2}, {"id": 3}]}`), []byte(`{"items": [{"id": 3}, {"id": 1, "a": 2}, {"id": 4}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  items: [
+    [0]: {
+      a: 1 vs. 2
+      … (1 key equal)
+    }
+    [1]: map[id:2] vs. <nil>
+    … (1 element equal)
+    [2]: <nil> vs. map[id:4]
+  ]
+}
+`
+	if buf.String() != expected {
+		t.Errorf("tree mismatch. want:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
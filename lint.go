@@ -0,0 +1,95 @@
+package jsonassert
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ruleStats records how a rule was used by the comparisons that looked it up.
+type ruleStats struct {
+	matches int64
+
+	mu       sync.Mutex
+	conflict *ruleConflict
+}
+
+// ruleConflict is the first location where a rule had no effect because another rule ignored
+// the value.
+type ruleConflict struct {
+	location  string
+	ignoredBy string
+}
+
+func (s *ruleStats) addMatch() {
+	atomic.AddInt64(&s.matches, 1)
+}
+
+func (s *ruleStats) matched() bool {
+	return atomic.LoadInt64(&s.matches) > 0
+}
+
+func (s *ruleStats) addConflict(location, ignoredBy string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conflict == nil {
+		s.conflict = &ruleConflict{location: location, ignoredBy: ignoredBy}
+	}
+}
+
+func (s *ruleStats) firstConflict() *ruleConflict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conflict
+}
+
+// recordConflicts records the rules matching location whose matcher or tolerance has no effect
+// because another rule ignores the value.
+func (c *Comparer) recordConflicts(location string) {
+	var ignoredBy string
+	for _, rule := range c.rules {
+		if rule.ignore && rule.pattern.match(location) {
+			ignoredBy = rule.pattern.path
+			break
+		}
+	}
+	for _, rule := range c.rules {
+		if !rule.ignore && (rule.matcher != nil || rule.hasTolerance || rule.hasPercent) && rule.pattern.match(location) {
+			rule.stats.addConflict(location, ignoredBy)
+		}
+	}
+}
+
+// LintRules reports the path rules of c that matched no path in any comparison so far, and the
+// rules that conflict: rules that both ignore a value and set a tolerance or regex for it,
+// rules with both a regex and a tolerance, and rules whose regex or tolerance had no effect
+// because another rule ignored the value. Rules are counted across every Comparer created with
+// the same option values, so a TestMain can lint the options set with SetDefaultOptions after
+// the tests run:
+//   code := m.Run()
+//   for _, err := range jsonassert.NewComparer().LintRules() {
+//     fmt.Fprintln(os.Stderr, err)
+//   }
+// Rule files grow as fixtures change, and these are the rules that no longer do anything.
+func (c *Comparer) LintRules() []error {
+	var errors []error
+	for _, rule := range c.rules {
+		path := rule.pattern.path
+		hasTolerance := rule.hasTolerance || rule.hasPercent
+		switch {
+		case rule.ignore && hasTolerance:
+			errors = append(errors, fmt.Errorf("rule for %s ignores the value and sets a tolerance for it", path))
+		case rule.ignore && rule.matcher != nil:
+			errors = append(errors, fmt.Errorf("rule for %s ignores the value and sets a regex for it", path))
+		case rule.matcher != nil && hasTolerance:
+			errors = append(errors, fmt.Errorf("rule for %s sets a regex and a tolerance, so the tolerance has no effect", path))
+		}
+		if !rule.stats.matched() && !rule.fromPreset {
+			errors = append(errors, fmt.Errorf("rule for %s matched no path", path))
+		}
+		if conflict := rule.stats.firstConflict(); conflict != nil {
+			errors = append(errors, fmt.Errorf("rule for %s has no effect at %s, which is ignored by the rule for %s", path, conflict.location, conflict.ignoredBy))
+		}
+	}
+	return errors
+}
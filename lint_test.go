@@ -0,0 +1,43 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLintRules(t *testing.T) {
+	tolerance := 0.01
+	tests := []struct {
+		name           string
+		opts           []Option
+		json           string
+		expectedErrors []error
+	}{
+		{"all used", []Option{WithIgnorePaths("a"), WithRules(map[string]Rule{"b": {Tolerance: &tolerance}})}, `{"a": 1, "b": 2}`, nil},
+		{"unused", []Option{WithIgnorePaths("a", "c"), WithMatcher("items[*].id", MatchRegexp("^x"))}, `{"a": 1, "items": []}`, []error{
+			fmt.Errorf("rule for c matched no path"),
+			fmt.Errorf("rule for items[*].id matched no path"),
+		}},
+		{"conflicting rule", []Option{WithRules(map[string]Rule{"a": {Ignore: true, Tolerance: &tolerance}, "b": {Regex: "^x", Tolerance: &tolerance}})}, `{"a": 1, "b": "x"}`, []error{
+			fmt.Errorf("rule for a ignores the value and sets a tolerance for it"),
+			fmt.Errorf("rule for b sets a regex and a tolerance, so the tolerance has no effect"),
+		}},
+		{"conflicting rules", []Option{WithIgnorePaths("meta.*"), WithRules(map[string]Rule{"meta.total": {Tolerance: &tolerance}})}, `{"meta": {"id": 1, "total": 2}}`, []error{
+			fmt.Errorf("rule for meta.total has no effect at meta.total, which is ignored by the rule for meta.*"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComparer(tt.opts...)
+			checkErrors(t, nil, c.EqualMap([]byte(tt.json), []byte(tt.json)))
+			checkErrors(t, tt.expectedErrors, c.LintRules())
+		})
+	}
+}
+
+func TestLintRulesSharedOptions(t *testing.T) {
+	opt := WithIgnorePaths("a", "b")
+	NewComparer(opt).EqualMap([]byte(`{"a": 1}`), []byte(`{"a": 1}`))
+	NewComparer(opt).EqualMap([]byte(`{"b": 1}`), []byte(`{"b": 1}`))
+	checkErrors(t, nil, NewComparer(opt).LintRules())
+}
@@ -0,0 +1,175 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// literalPrefix marks the strings that quoteLiterals substitutes for the literals encoding/json
+// doesn't accept. It starts with a NUL so that it can't be confused with the strings in real
+// documents.
+const literalPrefix = "\x00jsonassert:"
+
+// AllowNonFinite accepts the NaN, Infinity and -Infinity literals that some encoders outside Go
+// write for floating point values that JSON can't represent, and which encoding/json rejects.
+// They compare equal to the same literal, so NaN is equal to NaN, and differ from every other
+// value. Error messages show them as written.
+func AllowNonFinite() Option {
+	return func(c *Comparer) {
+		c.allowNonFinite = true
+	}
+}
+
+// AllowLenientNumbers accepts numbers spelled in ways JSON doesn't allow but lenient producers
+// write anyway: a leading plus sign (+1), leading zeros (007), and a decimal point with no digits
+// on one side (.5 and 5.). They are compared by value, so +1 is equal to 1. Without this option
+// they are reported as syntax errors that name the number.
+func AllowLenientNumbers() Option {
+	return func(c *Comparer) {
+		c.allowLenientNumbers = true
+	}
+}
+
+// DistinguishNegativeZero considers -0 different from 0. By default they are equal, since most
+// encoders write the negative zero of a float as -0 without it meaning anything.
+func DistinguishNegativeZero() Option {
+	return func(c *Comparer) {
+		c.distinguishNegativeZero = true
+	}
+}
+
+// LexicalNumbers considers numbers equal only if they are written the same way, so 1, 1.0 and
+// 1e0 differ, as do -0 and 0. By default numbers are compared by value, which suits round trips
+// through encoders that write numbers differently, while checks of canonical output need to
+// catch a change in how a number is written. Tolerances and WithDecimal don't apply to numbers
+// compared lexically. A number and null are still compared as set by Strict.
+func LexicalNumbers() Option {
+	return func(c *Comparer) {
+		c.lexicalNumbers = true
+	}
+}
+
+// nonFiniteLiterals are the literals accepted by AllowNonFinite.
+var nonFiniteLiterals = []string{"NaN", "Infinity", "-Infinity"}
+
+var lenientNumberRegexp = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// isLenientNumber reports whether token is a number accepted by AllowLenientNumbers but not by
+// encoding/json.
+func isLenientNumber(token string) bool {
+	return lenientNumberRegexp.MatchString(token) && !json.Valid([]byte(token))
+}
+
+func isNonFinite(token string) bool {
+	for _, literal := range nonFiniteLiterals {
+		if token == literal {
+			return true
+		}
+	}
+	return false
+}
+
+// isTokenEnd reports whether ch ends a literal.
+func isTokenEnd(ch byte) bool {
+	return strings.IndexByte("[{,]}: \t\r\n", ch) >= 0
+}
+
+// quoteLiterals replaces the literals outside of strings in text that the Comparer allows but
+// encoding/json doesn't with marked strings that encoding/json can decode.
+func (c *Comparer) quoteLiterals(text []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	var previous byte // the last byte outside of whitespace
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		if inString {
+			out.WriteByte(ch)
+			if ch == '\\' && i+1 < len(text) {
+				i++
+				out.WriteByte(text[i])
+			} else if ch == '"' {
+				inString = false
+				previous = ch
+			}
+			continue
+		}
+		if ch == '"' {
+			inString = true
+		}
+		if previous == 0 || strings.IndexByte("[,:", previous) >= 0 {
+			end := i
+			for end < len(text) && !isTokenEnd(text[end]) {
+				end++
+			}
+			token := string(text[i:end])
+			if c.allowNonFinite && isNonFinite(token) || c.allowLenientNumbers && isLenientNumber(token) {
+				quoted, _ := json.Marshal(literalPrefix + token)
+				out.Write(quoted)
+				i = end - 1
+				previous = '"'
+				continue
+			}
+		}
+		out.WriteByte(ch)
+		if strings.IndexByte(" \t\r\n", ch) < 0 {
+			previous = ch
+		}
+	}
+	return out.Bytes()
+}
+
+// unquoteLiterals replaces the marked strings written by quoteLiterals with json.Numbers holding
+// the original literals.
+func unquoteLiterals(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, literalPrefix) {
+			return json.Number(strings.TrimPrefix(v, literalPrefix))
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = unquoteLiterals(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = unquoteLiterals(item)
+		}
+	}
+	return value
+}
+
+// explainSyntaxError adds the option that accepts the literal at the position of a syntax error
+// in text, or that accepts the values after the first one, if there is one, to the error.
+func explainSyntaxError(text []byte, err error) error {
+	syntaxErr, ok := err.(*json.SyntaxError)
+	if !ok {
+		return err
+	}
+	if _, splitErr := splitValues(text); splitErr == nil && strings.HasSuffix(syntaxErr.Error(), "after top-level value") {
+		return fmt.Errorf("%v (concatenated values are accepted by AllowConcatenated)", err)
+	}
+	pos := int(syntaxErr.Offset) - 1
+	if pos >= len(text) || pos > 0 && isTokenEnd(text[pos]) {
+		pos--
+	}
+	if pos < 0 || isTokenEnd(text[pos]) {
+		return err
+	}
+	start, end := pos, pos
+	for start > 0 && !isTokenEnd(text[start-1]) {
+		start--
+	}
+	for end < len(text) && !isTokenEnd(text[end]) {
+		end++
+	}
+	switch token := string(text[start:end]); {
+	case isNonFinite(token):
+		return fmt.Errorf("%v (%s is accepted by AllowNonFinite)", err, token)
+	case isLenientNumber(token):
+		return fmt.Errorf("%v (nonstandard number %s is accepted by AllowLenientNumbers)", err, token)
+	}
+	return err
+}
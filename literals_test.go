@@ -0,0 +1,93 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAllowNonFinite(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"rejected by default", nil, `{"a": NaN}`, `[-Infinity]`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character 'N' looking for beginning of value (NaN is accepted by AllowNonFinite)"),
+			fmt.Errorf("error unmarshalling json2: invalid character 'I' in numeric literal (-Infinity is accepted by AllowNonFinite)"),
+		}},
+		{"equal", []Option{AllowNonFinite()}, `{"a": NaN, "b": [Infinity, -Infinity], "c": {"d": NaN}}`, `{"a":NaN,"b":[Infinity,-Infinity],"c":{"d":NaN}}`, nil},
+		{"different", []Option{AllowNonFinite(), WithinPercent(1)}, `{"a": NaN, "b": Infinity, "c": 1}`, `{"a": 1, "b": -Infinity, "c": NaN}`, []error{
+			fmt.Errorf("a mismatch. NaN vs. 1"),
+			fmt.Errorf("b mismatch. Infinity vs. -Infinity"),
+			fmt.Errorf("c mismatch. 1 vs. NaN"),
+		}},
+		{"strings are unchanged", []Option{AllowNonFinite()}, `{"a": "NaN", "b": "x\" NaN"}`, `{"a": NaN, "b": "x\" NaN"}`, []error{
+			fmt.Errorf(`a mismatch. "NaN" vs. NaN`),
+		}},
+		{"not a literal", []Option{AllowNonFinite()}, `{"a": NaNa}`, `{}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character 'N' looking for beginning of value"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestAllowNonFiniteSlice(t *testing.T) {
+	c := NewComparer(AllowNonFinite())
+	checkErrors(t, nil, c.EqualSlice([]byte(`[NaN, 1]`), []byte(`[NaN, 1]`)))
+	checkErrors(t, []error{fmt.Errorf("[0] mismatch. NaN vs. 0")}, c.EqualSlice([]byte(`[NaN]`), []byte(`[0]`)))
+}
+
+func TestAllowLenientNumbers(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"rejected by default", nil, `{"a": +1}`, `{"a": 007}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character '+' looking for beginning of value (nonstandard number +1 is accepted by AllowLenientNumbers)"),
+			fmt.Errorf("error unmarshalling json2: invalid character '0' after object key:value pair (nonstandard number 007 is accepted by AllowLenientNumbers)"),
+		}},
+		{"trailing point rejected", nil, `[5.]`, `[.5]`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character ']' in numeric literal (nonstandard number 5. is accepted by AllowLenientNumbers)"),
+			fmt.Errorf("error unmarshalling json2: invalid character '.' looking for beginning of value (nonstandard number .5 is accepted by AllowLenientNumbers)"),
+		}},
+		{"other errors unchanged", nil, `{"a": x}`, `{}`, []error{fmt.Errorf("error unmarshalling json1: invalid character 'x' looking for beginning of value")}},
+		{"accepted", []Option{AllowLenientNumbers()}, `{"a": +1, "b": 007, "c": [5., .5], "d": -.5e1}`, `{"a": 1, "b": 7, "c": [5, 0.5], "d": -5}`, nil},
+		{"shown as written", []Option{AllowLenientNumbers()}, `{"a": +1}`, `{"a": 2}`, []error{fmt.Errorf("a mismatch. +1 vs. 2")}},
+		{"with non-finite", []Option{AllowLenientNumbers(), AllowNonFinite()}, `[+1, NaN, "+1"]`, `[1, NaN, "+1"]`, nil},
+		{"negative zero equal by default", nil, `{"a": -0, "b": -0.0}`, `{"a": 0, "b": 0}`, nil},
+		{"negative zero distinguished", []Option{DistinguishNegativeZero()}, `{"a": -0, "b": -0.0, "c": -0, "d": 0.0}`, `{"a": 0, "b": 0, "c": -0.0, "d": 0}`, []error{
+			fmt.Errorf("a mismatch. -0 vs. 0"),
+			fmt.Errorf("b mismatch. -0.0 vs. 0"),
+		}},
+		{"lexical", []Option{LexicalNumbers()}, `{"a": 1, "b": 1.0, "c": 1e0, "d": -0, "e": 12.50, "f": null, "g": 0}`, `{"a": 1, "b": 1, "c": 1, "d": 0, "e": 12.50}`, []error{
+			fmt.Errorf("b mismatch. 1.0 vs. 1"),
+			fmt.Errorf("c mismatch. 1e0 vs. 1"),
+			fmt.Errorf("d mismatch. -0 vs. 0"),
+		}},
+		{"lexical ignores tolerance", []Option{LexicalNumbers(), WithTolerance(1)}, `[1, 2]`, `[1.0, 2]`, []error{
+			fmt.Errorf("[0] mismatch. 1 vs. 1.0"),
+		}},
+		{"lexical non-finite", []Option{LexicalNumbers(), AllowNonFinite()}, `[NaN, 1]`, `[NaN, Infinity]`, []error{
+			fmt.Errorf("[1] mismatch. 1 vs. Infinity"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			if tt.json1[0] == '[' {
+				errs = NewComparer(tt.opts...).EqualSlice([]byte(tt.json1), []byte(tt.json2))
+			}
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
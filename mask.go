@@ -0,0 +1,268 @@
+package jsonassert
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"regexp"
+	"unicode"
+)
+
+// ssnRegexp matches numbers formatted like US Social Security numbers.
+var ssnRegexp = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+
+// MaskPHI keeps protected health information out of error messages and trees, so assertion
+// failures can't leak it into CI logs. Values are still compared as written; only the way they
+// are shown changes. Anything formatted like a Social Security number, such as 123-45-6789, is
+// masked in every string, and the values at the given paths, such as dates of birth and names,
+// are masked entirely. Masking replaces each letter and digit with '*', so "1980-04-02" is shown
+// as "****-**-**". Paths use the notation described on WithIgnorePaths:
+//   jsonassert.MaskPHI("patient.dob", "patient.name", "claims[*].member.*")
+// StructCheck also masks strings before analyzing them, so its suggestions don't show them
+// either.
+func MaskPHI(paths ...string) Option {
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].mask = true
+	}
+	addRules := withPathRules(rules)
+	return func(c *Comparer) {
+		c.maskPHI = true
+		addRules(c)
+	}
+}
+
+// masked reports whether a rule masks the value at location. It doesn't count as a match for
+// LintRules, since ruleFor counts the rule when the value is compared.
+func (c *Comparer) masked(location string) bool {
+	for _, rule := range c.rules {
+		if rule.mask && rule.pattern.match(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskMode says what a value masked by maskValue is used for.
+type maskMode int
+
+const (
+	// maskForAnalysis keeps the types of the values, so StructCheck can still analyze them.
+	maskForAnalysis maskMode = iota
+	// maskForReport keeps the types of scrubbed values, but replaces masked numbers with
+	// strings, for the Expected and Actual fields of a Mismatch.
+	maskForReport
+	// maskForDisplay replaces masked numbers and scrubbed values of any type with strings, for
+	// values that are only shown.
+	maskForDisplay
+)
+
+// maskValue returns a copy of v, the value at location, with the values nested in it masked as
+// set by MaskPHI and replaced as set by Scrub, for the use given by mode.
+func (c *Comparer) maskValue(location string, v interface{}, mode maskMode) interface{} {
+	if h := c.hashed(location); h != nil && v != nil {
+		if mode == maskForDisplay {
+			return h.sum(v)
+		}
+		return scrubLeaves(v, h.sum(v))
+	}
+	if replacement, ok := c.scrubbed(location); ok && v != nil {
+		if mode == maskForDisplay {
+			return replacement
+		}
+		return scrubLeaves(v, replacement)
+	}
+	switch v := v.(type) {
+	case string:
+		if c.masked(location) {
+			return maskString(v)
+		}
+		if c.maskPHI {
+			return ssnRegexp.ReplaceAllStringFunc(v, maskString)
+		}
+	case json.Number:
+		if mode != maskForAnalysis && c.masked(location) {
+			return json.Number(maskString(string(v)))
+		}
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			masked[key] = c.maskValue(getLocation(location, key), value, mode)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, value := range v {
+			masked[i] = c.maskValue(fmt.Sprintf("%s[%d]", location, i), value, mode)
+		}
+		return masked
+	}
+	return v
+}
+
+// Scrub replaces the values at the given paths with replacement before they are compared or
+// shown, for secrets like tokens and account numbers. Unlike WithIgnorePaths, the values must
+// still be present and have the same JSON type, so a token that turns into a number or goes
+// missing is reported, as in "token mismatch. [scrubbed] (string) vs. [scrubbed] (number)", but
+// the values themselves never appear in error messages, trees or StructCheck suggestions. An
+// empty replacement is written as "[scrubbed]". Paths use the notation described on
+// WithIgnorePaths.
+func Scrub(replacement string, paths ...string) Option {
+	if replacement == "" {
+		replacement = "[scrubbed]"
+	}
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].scrub = replacement
+	}
+	return withPathRules(rules)
+}
+
+// scrubbed returns the replacement for the value at location if a rule scrubs it. Like masked,
+// it doesn't count as a match for LintRules.
+func (c *Comparer) scrubbed(location string) (string, bool) {
+	for _, rule := range c.rules {
+		if rule.scrub != "" && rule.pattern.match(location) {
+			return rule.scrub, true
+		}
+	}
+	return "", false
+}
+
+// compareScrubbed compares scrubbed values by their JSON types. Null is equal to the default
+// value of each type, as for other values, unless the Comparer is strict.
+func (c *Comparer) compareScrubbed(location string, value1, value2 interface{}, replacement string) []error {
+	type1, type2 := jsonType(value1), jsonType(value2)
+	if type1 == type2 || !c.strict && (value1 == nil && c.equalsNil(value2) || value2 == nil && c.equalsNil(value1)) {
+		return nil
+	}
+	describe := func(value interface{}, typ string) string {
+		if value == nil {
+			return "<nil>"
+		}
+		return fmt.Sprintf("%s (%s)", replacement, typ)
+	}
+	return []error{c.mismatch(mismatchKind(value1, value2), location, value1, value2, describe(value1, type1), describe(value2, type2))}
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "array"
+}
+
+// scrubLeaves replaces the strings nested in v with replacement and the numbers with 0, keeping
+// the types of the values.
+func scrubLeaves(v interface{}, replacement string) interface{} {
+	switch v := v.(type) {
+	case string:
+		return replacement
+	case json.Number:
+		return json.Number("0")
+	case map[string]interface{}:
+		scrubbed := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			scrubbed[key] = scrubLeaves(value, replacement)
+		}
+		return scrubbed
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, value := range v {
+			scrubbed[i] = scrubLeaves(value, replacement)
+		}
+		return scrubbed
+	}
+	return v
+}
+
+// CompareHashes compares the values at the given paths by their SHA-256 hashes, so equality is
+// still verified but error messages and trees show only a prefix of each hash, such as
+// "sha256:2c26b46b68ff", instead of the values. With a key, the hashes are HMAC-SHA256 hashes,
+// which can't be reversed by hashing guesses, so use one for values with few possibilities, such
+// as account numbers. Values are hashed in their JSON encoding with object keys sorted, so 1
+// and 1.0 are different. Paths use the notation described on WithIgnorePaths.
+func CompareHashes(key []byte, paths ...string) Option {
+	h := &valueHash{key: append([]byte(nil), key...)}
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].hash = h
+	}
+	return withPathRules(rules)
+}
+
+// valueHash hashes values for CompareHashes.
+type valueHash struct {
+	key []byte
+}
+
+// digest returns the hash of v.
+func (h *valueHash) digest(v interface{}) []byte {
+	encoded, _ := json.Marshal(v)
+	var hasher hash.Hash = sha256.New()
+	if len(h.key) > 0 {
+		hasher = hmac.New(sha256.New, h.key)
+	}
+	hasher.Write(encoded)
+	return hasher.Sum(nil)
+}
+
+// sum returns a prefix of the hash of v for error messages, marked with the hash function.
+func (h *valueHash) sum(v interface{}) string {
+	name := "sha256"
+	if len(h.key) > 0 {
+		name = "hmac-sha256"
+	}
+	return name + ":" + hex.EncodeToString(h.digest(v))[:12]
+}
+
+// hashed returns the hash for the value at location if a rule compares it by hash. Like masked,
+// it doesn't count as a match for LintRules.
+func (c *Comparer) hashed(location string) *valueHash {
+	for _, rule := range c.rules {
+		if rule.hash != nil && rule.pattern.match(location) {
+			return rule.hash
+		}
+	}
+	return nil
+}
+
+// compareHashes compares values by their hashes. Null is equal to the default value of each
+// type, as for other values, unless the Comparer is strict.
+func (c *Comparer) compareHashes(location string, value1, value2 interface{}, h *valueHash) []error {
+	if value1 == nil || value2 == nil {
+		if value1 == value2 || !c.strict && (value1 == nil && c.equalsNil(value2) || value2 == nil && c.equalsNil(value1)) {
+			return nil
+		}
+	} else if hmac.Equal(h.digest(value1), h.digest(value2)) {
+		return nil
+	}
+	return []error{c.notifyError(location, value1, value2)}
+}
+
+// maskString replaces each letter and digit in s with '*'.
+func maskString(s string) string {
+	masked := []rune(s)
+	for i, r := range masked {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			masked[i] = '*'
+		}
+	}
+	return string(masked)
+}
@@ -0,0 +1,177 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMaskPHI(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"shown by default", nil, `{"ssn": "123-45-6789"}`, `{"ssn": "123-45-6780"}`, []error{
+			fmt.Errorf(`ssn mismatch. "123-45-6789" vs. "123-45-6780"`),
+		}},
+		{"equal", []Option{MaskPHI("dob")}, `{"ssn": "123-45-6789", "dob": "1980-04-02"}`, `{"ssn": "123-45-6789", "dob": "1980-04-02"}`, nil},
+		{"social security numbers", []Option{MaskPHI()}, `{"note": "SSN 123-45-6789 on file", "id": "A1"}`, `{"note": "SSN 123-45-6780 on file", "id": "A2"}`, []error{
+			fmt.Errorf(`id mismatch. "A1" vs. "A2"`),
+			fmt.Errorf(`note mismatch. "SSN ***-**-**** on file" vs. "SSN ***-**-**** on file"`),
+		}},
+		{"paths", []Option{MaskPHI("patient.dob", "patient.name", "patient.mrn")}, `{"patient": {"dob": "1980-04-02", "name": "José Díaz", "mrn": 1234}}`, `{"patient": {"dob": "1980-04-20", "name": "Jose Diaz", "mrn": 1235}}`, []error{
+			fmt.Errorf(`patient.dob mismatch. "****-**-**" vs. "****-**-**"`),
+			fmt.Errorf(`patient.mrn mismatch. **** vs. ****`),
+			fmt.Errorf(`patient.name mismatch. "**** ****" vs. "**** ****"`),
+		}},
+		{"nested values", []Option{MaskPHI("members[*].name"), Strict()}, `{"members": [{"name": "Ann", "state": "TX"}]}`, `{"members": null}`, []error{
+			fmt.Errorf(`members mismatch. [map[name:*** state:TX]] vs. <nil>`),
+		}},
+		{"matchers", []Option{MaskPHI("dob"), WithMatcher("dob", MatchRegexp(`^\d{4}-\d{2}-\d{2}$`))}, `{"dob": "04/02/1980"}`, `{"dob": "1980-04-02"}`, []error{
+			fmt.Errorf(`dob mismatch. "**/**/****" doesn't match regexp "^\\d{4}-\\d{2}-\\d{2}$"`),
+		}},
+		{"strict presence", []Option{MaskPHI("name"), StrictPresence()}, `{"name": "Ann"}`, `{}`, []error{
+			fmt.Errorf(`name mismatch. "***" vs. <absent>`),
+		}},
+		{"rule", []Option{WithRules(map[string]Rule{"name": {Mask: true}})}, `{"name": "Ann", "ssn": "123-45-6789"}`, `{"name": "Bob", "ssn": "123-45-6780"}`, []error{
+			fmt.Errorf(`name mismatch. "***" vs. "***"`),
+			fmt.Errorf(`ssn mismatch. "123-45-6789" vs. "123-45-6780"`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestMaskPHIReport(t *testing.T) {
+	result := Compare([]byte(`{"patient": {"memberId": 123456789}}`), []byte(`{"patient": {"memberId": 987654321}}`), MaskPHI("patient.memberId"))
+	m := result.Errors()[0]
+	if m.Expected != "*********" || m.Actual != "*********" {
+		t.Errorf("expected masked values, got %v and %v", m.Expected, m.Actual)
+	}
+	data, err := result.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"ok":false,"mismatches":[{"path":"patient.memberId","kind":"value","code":"JA003","expected":"*********","actual":"*********","message":"patient.memberId mismatch. ********* vs. *********"}],"stats":{"values":3,"ignored":0,"mismatches":1}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestMaskPHITree(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewComparer(MaskPHI("patient.name"))
+	if err := c.WriteTree(&buf, []byte(`{"patient": {"name": "Ann", "ssn": "123-45-6789"}}`), []byte(`{"patient": {"name": "Bob", "ssn": "123-45-6780"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  patient: {
+    name: "***" vs. "***"
+    ssn: "***-**-****" vs. "***-**-****"
+  }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("tree mismatch. want:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestMaskPHIStructCheck(t *testing.T) {
+	fakeT := &fakeTester{}
+	NewComparer(MaskPHI("obj.a")).StructCheck(fakeT, "testdata/complete.json", &subStruct{})
+	for _, err := range fakeT.errors {
+		if bytes.Contains([]byte(err.Error()), []byte(`"val"`)) {
+			t.Errorf("masked value in %v", err)
+		}
+	}
+	if len(fakeT.errors) == 0 {
+		t.Error("expected errors")
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"same types", []Option{Scrub("", "token", "account.*")}, `{"token": "abc", "account": {"number": 1234, "pin": "0000"}}`, `{"token": "xyz", "account": {"number": 5678, "pin": "9999"}}`, nil},
+		{"different types", []Option{Scrub("", "token", "card")}, `{"token": "abc", "card": {"number": "4111"}}`, `{"token": 123, "card": ["4111"]}`, []error{
+			fmt.Errorf("card mismatch. [scrubbed] (object) vs. [scrubbed] (array)"),
+			fmt.Errorf("token mismatch. [scrubbed] (string) vs. [scrubbed] (number)"),
+		}},
+		{"missing", []Option{Scrub("<secret>", "token")}, `{"token": "abc"}`, `{}`, []error{
+			fmt.Errorf("token mismatch. <secret> (string) vs. <nil>"),
+		}},
+		{"empty and missing", []Option{Scrub("<secret>", "token")}, `{"token": ""}`, `{}`, nil},
+		{"strict", []Option{Scrub("<secret>", "token"), Strict()}, `{"token": ""}`, `{"token": null}`, []error{
+			fmt.Errorf("token mismatch. <secret> (string) vs. <nil>"),
+		}},
+		{"strict presence", []Option{Scrub("<secret>", "token"), StrictPresence()}, `{"token": "abc"}`, `{}`, []error{
+			fmt.Errorf(`token mismatch. "<secret>" vs. <absent>`),
+		}},
+		{"nested in a mismatch", []Option{Scrub("", "users[*].token"), Strict()}, `{"users": [{"id": 1, "token": "abc"}]}`, `{"users": null}`, []error{
+			fmt.Errorf(`users mismatch. [map[id:1 token:[scrubbed]]] vs. <nil>`),
+		}},
+		{"rule", []Option{WithRules(map[string]Rule{"token": {Scrub: "***"}})}, `{"token": true}`, `{"token": "abc"}`, []error{
+			fmt.Errorf("token mismatch. *** (boolean) vs. *** (string)"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestScrubLeaves(t *testing.T) {
+	c := NewComparer(Scrub("x", "a"))
+	value := map[string]interface{}{"a": map[string]interface{}{"b": "secret", "c": []interface{}{json.Number("12"), true, nil}}, "d": "shown"}
+	expected := map[string]interface{}{"a": map[string]interface{}{"b": "x", "c": []interface{}{json.Number("0"), true, nil}}, "d": "shown"}
+	if actual := c.maskValue("", value, maskForAnalysis); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestCompareHashes(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"equal", []Option{CompareHashes(nil, "token", "account")}, `{"token": "abc", "account": {"b": 2, "a": 1}}`, `{"token": "abc", "account": {"a": 1, "b": 2}}`, nil},
+		{"different", []Option{CompareHashes(nil, "token")}, `{"token": "abc"}`, `{"token": "abd"}`, []error{
+			fmt.Errorf(`token mismatch. "sha256:6cc43f858fbb" vs. "sha256:498b046d97f2"`),
+		}},
+		{"hmac", []Option{CompareHashes([]byte("key"), "token")}, `{"token": "abc"}`, `{"token": "abd"}`, []error{
+			fmt.Errorf(`token mismatch. "hmac-sha256:7b83606bf498" vs. "hmac-sha256:356ba82c86ea"`),
+		}},
+		{"numbers as written", []Option{CompareHashes(nil, "n")}, `{"n": 1}`, `{"n": 1.0}`, []error{
+			fmt.Errorf(`n mismatch. "sha256:6b86b273ff34" vs. "sha256:d0ff5974b6aa"`),
+		}},
+		{"null", []Option{CompareHashes(nil, "a", "b")}, `{"a": null, "b": ""}`, `{"b": null}`, nil},
+		{"strict null", []Option{CompareHashes(nil, "b"), Strict()}, `{"b": ""}`, `{"b": null}`, []error{
+			fmt.Errorf(`b mismatch. "sha256:12ae32cb1ec0" vs. <nil>`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
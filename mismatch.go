@@ -0,0 +1,204 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Mismatch is a difference found by comparing two documents. The errors returned by EqualMap and
+// EqualSlice for differences are Mismatch values, so they can be filtered by path or kind
+// without parsing their messages:
+//   for _, err := range jsonassert.EqualMap(json1, json2) {
+//     var m jsonassert.Mismatch
+//     if errors.As(err, &m) && m.Kind == jsonassert.KindMissing {
+//       ...
+//     }
+//   }
+type Mismatch struct {
+	// Path is the location of the values that differ, in the notation set by WithPathFormat, or
+	// "" for the whole document.
+	Path string `json:"path"`
+	// Kind is the category of the difference.
+	Kind MismatchKind `json:"kind"`
+	// Code is the stable code of the Kind, such as JA001, for tools that shouldn't depend on the
+	// wording of messages.
+	Code string `json:"code"`
+	// Expected and Actual are the values at Path in json1 and json2, as decoded by
+	// encoding/json with numbers as json.Number, or nil if they are null or missing. They are
+	// masked and scrubbed as set by MaskPHI and Scrub, and numbers that aren't valid JSON, such
+	// as NaN, are given as strings.
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+	// Message describes the difference, as in the errors returned by EqualMap.
+	Message string `json:"message"`
+}
+
+func (m Mismatch) Error() string {
+	return m.Message
+}
+
+// MismatchKind is the category of a Mismatch.
+type MismatchKind string
+
+const (
+	// KindValue is a difference between two values of the same type.
+	KindValue MismatchKind = "value"
+	// KindType is a difference between values of different JSON types.
+	KindType MismatchKind = "type"
+	// KindMissing is a value in json1 that is null or missing in json2.
+	KindMissing MismatchKind = "missing"
+	// KindExtra is a value in json2 that is null or missing in json1.
+	KindExtra MismatchKind = "extra"
+	// KindLength is a difference in the lengths of two arrays.
+	KindLength MismatchKind = "length"
+	// KindPattern is a value that doesn't match the Matcher for its path.
+	KindPattern MismatchKind = "pattern"
+	// KindInvalid is a document that can't be read, such as one that isn't valid JSON.
+	KindInvalid MismatchKind = "invalid"
+)
+
+// kinds describes each kind of mismatch, in the order of their codes. A code never changes
+// meaning once it is assigned, so new kinds get new codes.
+var kinds = []struct {
+	kind        MismatchKind
+	code        string
+	description string
+	noun        string // counts the mismatches of the kind, for GroupByKind
+}{
+	{KindMissing, "JA001", "a value in json1 is null or missing in json2", "missing value"},
+	{KindType, "JA002", "the values have different JSON types", "type mismatch"},
+	{KindValue, "JA003", "the values have the same type but differ", "value mismatch"},
+	{KindExtra, "JA004", "a value in json2 is null or missing in json1", "extra value"},
+	{KindLength, "JA005", "the arrays have different lengths", "length mismatch"},
+	{KindPattern, "JA006", "a value doesn't match the Matcher for its path", "pattern mismatch"},
+	{KindInvalid, "JA007", "a document can't be read", "invalid document"},
+}
+
+// Code returns the stable code of the kind, or "" if it has none.
+func (k MismatchKind) Code() string {
+	for _, info := range kinds {
+		if info.kind == k {
+			return info.code
+		}
+	}
+	return ""
+}
+
+// WithErrorCodes starts each mismatch message with the code of its kind, as in
+// "[JA001] a mismatch. 1 vs. <nil>", so that log-based alerts and suppression lists can match
+// the code instead of the wording. The codes are:
+//   JA001  a value in json1 is null or missing in json2
+//   JA002  the values have different JSON types
+//   JA003  the values have the same type but differ
+//   JA004  a value in json2 is null or missing in json1
+//   JA005  the arrays have different lengths
+//   JA006  a value doesn't match the Matcher for its path
+//   JA007  a document can't be read
+// Messages written by WithMessageTemplate include the code only if the template does.
+func WithErrorCodes() Option {
+	return func(c *Comparer) {
+		c.errorCodes = true
+	}
+}
+
+// asMismatch returns err as a Mismatch, or as a Mismatch of KindInvalid with an empty path if it
+// isn't one.
+func asMismatch(err error) Mismatch {
+	if m, ok := err.(Mismatch); ok {
+		return m
+	}
+	return Mismatch{Kind: KindInvalid, Code: KindInvalid.Code(), Message: err.Error()}
+}
+
+// invalidDocument creates the Mismatch for a document that can't be unmarshalled.
+func (c *Comparer) invalidDocument(document string, err error) Mismatch {
+	m := Mismatch{Kind: KindInvalid, Code: KindInvalid.Code(), Message: fmt.Sprintf("error unmarshalling %s: %v", document, err)}
+	if c.errorCodes {
+		m.Message = fmt.Sprintf("[%s] %s", m.Code, m.Message)
+	}
+	return m
+}
+
+// mismatchKind categorizes the difference between value1 and value2.
+func mismatchKind(value1, value2 interface{}) MismatchKind {
+	type1, type2 := jsonType(value1), jsonType(value2)
+	switch {
+	case type1 == "null":
+		return KindExtra
+	case type2 == "null":
+		return KindMissing
+	case type1 != type2:
+		return KindType
+	case type1 == "array":
+		return KindLength
+	}
+	return KindValue
+}
+
+// MessageData is the data given to the template set by WithMessageTemplate.
+type MessageData struct {
+	// Path is the location of the values that differ.
+	Path string
+	// Expected is the value in json1 as it is shown in messages, or for a KindPattern mismatch,
+	// the Matcher.
+	Expected string
+	// Actual is the value in json2 as it is shown in messages, or for a KindPattern mismatch,
+	// the value that doesn't match.
+	Actual string
+	// Kind is the category of the difference.
+	Kind MismatchKind
+	// Code is the stable code of the Kind.
+	Code string
+	// File is the fixture checked by StructCheck, or "" for other comparisons.
+	File string
+}
+
+// WithMessageTemplate writes mismatch messages with tmpl, executed with a MessageData, so that
+// failures can be worded consistently across projects and link to the runbook for updating
+// fixtures:
+//   tmpl := template.Must(template.New("").Parse(
+//     `{{.File}}: {{.Path}} is {{.Actual}}, want {{.Expected}} (see https://wiki.example.com/fixtures)`))
+//   c := jsonassert.NewComparer(jsonassert.WithMessageTemplate(tmpl))
+// Only mismatches between values use the template; errors reading the documents don't. If the
+// template fails, the default message is used, followed by the template error.
+func WithMessageTemplate(tmpl *template.Template) Option {
+	return func(c *Comparer) {
+		c.messageTemplate = tmpl
+	}
+}
+
+// mismatch creates the Mismatch of the given kind for value1 and value2 at location, shown as
+// expected and actual.
+func (c *Comparer) mismatch(kind MismatchKind, location string, value1, value2 interface{}, expected, actual string) Mismatch {
+	path := c.formatPath(location)
+	m := Mismatch{
+		Path:     path,
+		Kind:     kind,
+		Code:     kind.Code(),
+		Expected: encodableNumbers(c.maskValue(location, value1, maskForReport)),
+		Actual:   encodableNumbers(c.maskValue(location, value2, maskForReport)),
+	}
+	switch {
+	case kind == KindPattern:
+		m.Message = fmt.Sprintf("%s mismatch. %s doesn't match %s", path, actual, expected)
+	case strings.Contains(expected, "\n") || strings.Contains(actual, "\n"):
+		m.Message = fmt.Sprintf("%s mismatch.\njson1:\n%s\njson2:\n%s", path, expected, actual)
+	default:
+		m.Message = fmt.Sprintf("%s mismatch. %s vs. %s", path, expected, actual)
+	}
+	if c.errorCodes {
+		m.Message = fmt.Sprintf("[%s] %s", m.Code, m.Message)
+	}
+	if c.messageTemplate == nil {
+		return m
+	}
+	var message strings.Builder
+	data := MessageData{Path: path, Expected: expected, Actual: actual, Kind: kind, Code: m.Code, File: c.file}
+	if err := c.messageTemplate.Execute(&message, data); err != nil {
+		m.Message += fmt.Sprintf(" (message template: %v)", err)
+		return m
+	}
+	m.Message = message.String()
+	return m
+}
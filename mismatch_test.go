@@ -0,0 +1,138 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"text/template"
+)
+
+func TestMismatchKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		json1 string
+		json2 string
+		kind  MismatchKind
+	}{
+		{"value", nil, `{"a": 1}`, `{"a": 2}`, KindValue},
+		{"type", nil, `{"a": 1}`, `{"a": "1"}`, KindType},
+		{"missing", nil, `{"a": 1}`, `{}`, KindMissing},
+		{"extra", nil, `{}`, `{"a": "x"}`, KindExtra},
+		{"length", nil, `{"a": [1]}`, `{"a": [1, 2]}`, KindLength},
+		{"pattern", []Option{WithMatcher("a", MatchRegexp("^x"))}, `{"a": "x"}`, `{"a": "y"}`, KindPattern},
+		{"absent", []Option{StrictPresence()}, `{"a": null}`, `{}`, KindMissing},
+		{"numeric string", []Option{WithNumericStrings("a")}, `{"a": "1"}`, `{"a": 2}`, KindValue},
+		{"invalid", nil, `{`, `{}`, KindInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := Compare([]byte(tt.json1), []byte(tt.json2), tt.opts...).Errors()
+			if len(errors) != 1 || errors[0].Kind != tt.kind || errors[0].Code != tt.kind.Code() {
+				t.Errorf("mismatches = %+v, want one of kind %s", errors, tt.kind)
+			}
+		})
+	}
+}
+
+func TestWithErrorCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"codes", nil, `{"a": 1, "b": "x", "c": [1]}`, `{"a": 2, "b": 1, "d": true}`, []error{
+			fmt.Errorf("[JA003] a mismatch. 1 vs. 2"),
+			fmt.Errorf(`[JA002] b mismatch. "x" vs. 1`),
+			fmt.Errorf("[JA001] c mismatch. [1] vs. <nil>"),
+			fmt.Errorf("[JA004] d mismatch. <nil> vs. true"),
+		}},
+		{"pattern", []Option{WithMatcher("a", MatchRegexp("^x"))}, `{"a": "y"}`, `{}`, []error{
+			fmt.Errorf(`[JA006] a mismatch. "y" doesn't match regexp "^x"`),
+		}},
+		{"invalid", nil, `{`, `{}`, []error{
+			fmt.Errorf("[JA007] error unmarshalling json1: unexpected end of JSON input"),
+		}},
+		{"template", []Option{WithMessageTemplate(template.Must(template.New("").Parse(`{{.Code}} {{.Path}}`)))}, `{"a": 1}`, `{"a": 2}`, []error{
+			fmt.Errorf("JA003 a"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(append(tt.opts, WithErrorCodes())...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithMessageTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`{{.File}}: {{.Path}} is {{.Actual}}, want {{.Expected}} ({{.Kind}})`))
+	tests := []struct {
+		name           string
+		tmpl           *template.Template
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"fields", tmpl, nil, `{"a": 1, "b": "x"}`, `{"a": 2}`, []error{
+			fmt.Errorf(`: a is 2, want 1 (value)`),
+			fmt.Errorf(`: b is <nil>, want "x" (missing)`),
+		}},
+		{"pattern", tmpl, []Option{WithMatcher("a", MatchRegexp("^x"))}, `{}`, `{"a": "y"}`, []error{
+			fmt.Errorf(`: a is "y", want regexp "^x" (pattern)`),
+		}},
+		{"template error", template.Must(template.New("").Parse(`{{.Missing}}`)), nil, `{"a": 1}`, `{"a": 2}`, []error{
+			fmt.Errorf(`a mismatch. 1 vs. 2 (message template: template: :1:2: executing "" at <.Missing>: can't evaluate field Missing in type jsonassert.MessageData)`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(append(tt.opts, WithMessageTemplate(tt.tmpl))...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithMessageTemplateStructCheck(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`{{.File}}: {{.Path}}`))
+	fakeT := &fakeTester{}
+	NewComparer(WithMessageTemplate(tmpl)).StructCheck(fakeT, "testdata/complete.json", &struct {
+		Str string `json:"str"`
+	}{})
+	if len(fakeT.errors) < 2 || fakeT.errors[1].Error() != "testdata/complete.json: arr" {
+		t.Errorf("errors = %v", fakeT.errors)
+	}
+}
+
+func TestMismatchValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		json1    string
+		json2    string
+		expected interface{}
+		actual   interface{}
+	}{
+		{"values", nil, `{"a": {"b": [1]}}`, `{"a": {"b": "x"}}`, []interface{}{json.Number("1")}, "x"},
+		{"masked", []Option{MaskPHI("a")}, `{"a": "Jane"}`, `{"a": "John"}`, "****", "****"},
+		{"scrubbed", []Option{Scrub("", "a")}, `{"a": "secret"}`, `{"a": 1}`, "[scrubbed]", json.Number("0")},
+		{"non-finite", []Option{AllowNonFinite()}, `{"a": NaN}`, `{"a": 1}`, "NaN", json.Number("1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			var m Mismatch
+			if len(errs) != 1 || !errors.As(errs[0], &m) {
+				t.Fatalf("errors = %v, want one Mismatch", errs)
+			}
+			if !reflect.DeepEqual(m.Expected, tt.expected) || !reflect.DeepEqual(m.Actual, tt.actual) {
+				t.Errorf("Expected, Actual = %#v, %#v, want %#v, %#v", m.Expected, m.Actual, tt.expected, tt.actual)
+			}
+		})
+	}
+}
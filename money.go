@@ -0,0 +1,85 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// WithMoney compares the objects at the given paths as amounts of money in the form
+// {"amount": "10.5", "currency": "USD"}, where the amount is a number or a string holding a
+// number. The amounts are compared as exact decimals, or at the scale set by WithDecimal or a
+// Rule, so 10.5 and 10.50 are equal, and the currency codes are compared without regard to
+// case. A difference in either is reported as a single mismatch of the whole amount, such as
+// "price mismatch. 10.50 USD vs. 10.5 EUR". Any other keys in the objects are compared as usual.
+// Objects that aren't in this form are compared as usual. Paths use the notation described on
+// WithIgnorePaths.
+func WithMoney(paths ...string) Option {
+	rules := make([]pathRule, len(paths))
+	for i, path := range paths {
+		rules[i] = newPathRule(path)
+		rules[i].money = true
+	}
+	return withPathRules(rules)
+}
+
+// money is an amount of money read from an object.
+type money struct {
+	amount   json.Number
+	currency string
+}
+
+// describeMoney writes m, read from the object at location, for error messages, formatting the
+// amount like other numbers.
+func (c *Comparer) describeMoney(location string, m money) string {
+	return fmt.Sprintf("%s %s", c.quoteValue(getLocation(location, "amount"), m.amount), c.maskValue(getLocation(location, "currency"), m.currency, maskForDisplay))
+}
+
+// moneyValue reads value as an amount of money, if it is an object in the form WithMoney
+// accepts.
+func moneyValue(value interface{}) (money, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return money{}, false
+	}
+	amount, ok := numericValue(object["amount"])
+	if !ok {
+		return money{}, false
+	}
+	currency, ok := object["currency"].(string)
+	if !ok {
+		return money{}, false
+	}
+	return money{amount: amount, currency: currency}, true
+}
+
+// compareMoney compares value1 and value2 as amounts of money if both are. The second result is
+// false if they aren't, so they should be compared as usual.
+func (c *Comparer) compareMoney(location string, value1, value2 interface{}, rule pathRule) ([]error, bool) {
+	m1, ok1 := moneyValue(value1)
+	m2, ok2 := moneyValue(value2)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	scale, ok := c.decimalScale(rule)
+	if !ok {
+		scale = -1
+	}
+	tolerance, percent := c.numericTolerance(rule)
+	var errors []error
+	if !strings.EqualFold(m1.currency, m2.currency) || !decimalEqual(m1.amount, m2.amount, scale, tolerance, percent) {
+		errors = c.collect(errors, []error{c.mismatch(KindValue, location, value1, value2, c.describeMoney(location, m1), c.describeMoney(location, m2))})
+	}
+	rest1, rest2 := withoutMoneyKeys(value1), withoutMoneyKeys(value2)
+	return c.collect(errors, c.compareMaps(location, rest1, rest2)), true
+}
+
+func withoutMoneyKeys(value interface{}) map[string]interface{} {
+	rest := map[string]interface{}{}
+	for key, item := range value.(map[string]interface{}) {
+		if key != "amount" && key != "currency" {
+			rest[key] = item
+		}
+	}
+	return rest
+}
@@ -0,0 +1,46 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWithMoney(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"fields by default", nil, `{"price": {"amount": "10.50", "currency": "USD"}}`, `{"price": {"amount": "10.5", "currency": "EUR"}}`, []error{
+			fmt.Errorf(`price.amount mismatch. "10.50" vs. "10.5"`),
+			fmt.Errorf(`price.currency mismatch. "USD" vs. "EUR"`),
+		}},
+		{"equal", []Option{WithMoney("price", "items[*].total")}, `{"price": {"amount": "10.50", "currency": "USD"}, "items": [{"total": {"amount": 1, "currency": "usd"}}]}`, `{"price": {"amount": 10.5, "currency": "USD"}, "items": [{"total": {"amount": "1.00", "currency": "USD"}}]}`, nil},
+		{"different currency", []Option{WithMoney("price")}, `{"price": {"amount": "10.50", "currency": "USD"}}`, `{"price": {"amount": "10.5", "currency": "EUR"}}`, []error{
+			fmt.Errorf("price mismatch. 10.50 USD vs. 10.5 EUR"),
+		}},
+		{"different amount", []Option{WithMoney("price")}, `{"price": {"amount": "12345678901234567.89", "currency": "USD"}}`, `{"price": {"amount": "12345678901234567.88", "currency": "USD"}}`, []error{
+			fmt.Errorf("price mismatch. 12345678901234567.89 USD vs. 12345678901234567.88 USD"),
+		}},
+		{"scale", []Option{WithMoney("price"), WithDecimal(2)}, `{"price": {"amount": "10.004", "currency": "USD"}}`, `{"price": {"amount": "10", "currency": "USD"}}`, nil},
+		{"number format", []Option{WithMoney("price"), WithNumberFormat(FixedDecimals(2))}, `{"price": {"amount": 10, "currency": "USD"}}`, `{"price": {"amount": 11, "currency": "USD"}}`, []error{
+			fmt.Errorf("price mismatch. 10.00 USD vs. 11.00 USD"),
+		}},
+		{"other keys", []Option{WithMoney("price")}, `{"price": {"amount": 1, "currency": "USD", "note": "a"}}`, `{"price": {"amount": 1, "currency": "USD", "note": "b"}}`, []error{
+			fmt.Errorf(`price.note mismatch. "a" vs. "b"`),
+		}},
+		{"not money", []Option{WithMoney("price")}, `{"price": {"amount": "free", "currency": "USD"}}`, `{"price": null}`, []error{
+			fmt.Errorf(`price.amount mismatch. "free" vs. <nil>`),
+			fmt.Errorf(`price.currency mismatch. "USD" vs. <nil>`),
+		}},
+		{"rule", []Option{WithRules(map[string]Rule{"price": {Money: true}})}, `{"price": {"amount": "10.50", "currency": "USD"}}`, `{"price": {"amount": "10.5", "currency": "USD"}}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package jsonassert
+
+import (
+	"reflect"
+)
+
+// NullCheck reports every field of result that receives an explicit null from the JSON file but
+// isn't a pointer, slice, map or interface. Such a field decodes null to its zero value, so a
+// null in the JSON can't be told apart from "", 0, false or an empty struct. StructCheck
+// considers these values equal, so NullCheck helps API authors decide where a *T is needed to
+// preserve the difference, such as for PATCH requests where null means "clear the value".
+func NullCheck(t Testing, filename string, result interface{}) {
+	t.Helper()
+	_, value, ok := readCheckFile(t, filename, result)
+	if !ok {
+		return
+	}
+	for _, err := range findAmbiguousNulls("", reflect.TypeOf(result), value) {
+		t.Error(err)
+	}
+}
+
+// readCheckFile validates the result argument of an analysis function like NullCheck and reads
+// the JSON file, returning its text and its decoded value with numbers kept as json.Number.
+// Problems are reported to t and ok is false.
+func readCheckFile(t Testing, filename string, result interface{}) (text []byte, value interface{}, ok bool) {
+	t.Helper()
+	if _, err := resultArgCheck(result); err != nil {
+		t.Error(err)
+		return nil, nil, false
+	}
+	text, err := NewComparer().readFile(filename)
+	if err != nil {
+		t.Error(err)
+		return nil, nil, false
+	}
+	value, err = getJSONNumberValue(text)
+	if err != nil {
+		t.Errorf("error decoding json in %s: %v", filename, err)
+		return nil, nil, false
+	}
+	return text, value, true
+}
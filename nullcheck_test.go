@@ -0,0 +1,44 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+type nullableStruct struct {
+	Num      *float64   `json:"num"`
+	Str      *string    `json:"str"`
+	StrEmpty *string    `json:"str-empty"`
+	Arr      []string   `json:"arr"`
+	Obj      *subStruct `json:"obj"`
+}
+
+func TestNullCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"no nulls", "testdata/complete.json", &receiveStruct{}, nil},
+		{"nulls into values", "testdata/nulls.json", &receiveStruct{}, []error{
+			fmt.Errorf("b-false: explicit null is decoded into bool field jsonassert.receiveStruct.BFalse as its zero value false; use *bool if null is meaningful"),
+			fmt.Errorf("num-empty: explicit null is decoded into float64 field jsonassert.receiveStruct.NumEmpty as its zero value 0; use *float64 if null is meaningful"),
+			fmt.Errorf(`obj-empty: explicit null is decoded into jsonassert.subStruct field jsonassert.receiveStruct.ObjEmpty as its zero value {"a":"","b":""}; use *jsonassert.subStruct if null is meaningful`),
+			fmt.Errorf(`str-empty: explicit null is decoded into string field jsonassert.receiveStruct.StrEmpty as its zero value ""; use *string if null is meaningful`),
+		}},
+		{"nulls into pointers", "testdata/nulls.json", &nullableStruct{}, nil},
+		{"slice", "testdata/arrayNulls.json", &[]sliceStruct{}, []error{
+			fmt.Errorf(`[0].item1: explicit null is decoded into string field jsonassert.sliceStruct.Item1 as its zero value ""; use *string if null is meaningful`),
+			fmt.Errorf(`[1].item2: explicit null is decoded into string field jsonassert.sliceStruct.Item2 as its zero value ""; use *string if null is meaningful`),
+		}},
+		{"wrong result type", "testdata/nulls.json", &jsonComplete, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got *string")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			NullCheck(fakeT, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
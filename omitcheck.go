@@ -0,0 +1,139 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var isZeroerType = reflect.TypeOf((*interface{ IsZero() bool })(nil)).Elem()
+
+// OmitZeroCheck decodes the JSON file into result and reports every field tagged omitempty or
+// omitzero whose presence in the encoded JSON would differ between the two options. Use it
+// before replacing omitempty with omitzero (or the other way around) to see which keys would
+// appear or disappear from your encoded JSON. The main differences are:
+//   1. omitempty never omits structs, while omitzero omits zero structs such as a zero time.Time
+//   2. omitempty omits empty non-nil slices and maps, while omitzero keeps them
+//   3. omitzero calls the IsZero method of types that have one
+func OmitZeroCheck(t Testing, filename string, result interface{}) {
+	t.Helper()
+	text, _, ok := readCheckFile(t, filename, result)
+	if !ok {
+		return
+	}
+	if err := json.Unmarshal(text, result); err != nil {
+		t.Errorf("error decoding json in %s: %v", filename, err)
+		return
+	}
+	for _, err := range findOmitDifferences("", reflect.ValueOf(result)) {
+		t.Error(err)
+	}
+}
+
+// findOmitDifferences walks a decoded Go value and returns an error for each field tagged
+// omitempty or omitzero that one option would omit and the other would keep.
+func findOmitDifferences(location string, v reflect.Value) []error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var errors []error
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, field := range cachedTypeFields(v.Type()) {
+			fv, ok := fieldByIndex(v, field.index)
+			if !ok {
+				continue
+			}
+			fieldLocation := getLocation(location, field.name)
+			if field.omitEmpty || field.omitZero {
+				if err := omitDifference(fieldLocation, v.Type(), field, fv); err != nil {
+					errors = append(errors, err)
+				}
+			}
+			errors = append(errors, findOmitDifferences(fieldLocation, fv)...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		var mapKeys []string
+		values := map[string]reflect.Value{}
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			mapKeys = append(mapKeys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(mapKeys)
+		for _, key := range mapKeys {
+			errors = append(errors, findOmitDifferences(getLocation(location, key), values[key])...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errors = append(errors, findOmitDifferences(fmt.Sprintf("%s[%d]", location, i), v.Index(i))...)
+		}
+	}
+	return errors
+}
+
+func omitDifference(location string, owner reflect.Type, field structField, v reflect.Value) error {
+	empty, zero := isEmptyValue(v), isZeroValue(v)
+	if empty == zero {
+		return nil
+	}
+	encoded, _ := json.Marshal(v.Interface())
+	if empty {
+		return fmt.Errorf("%s: field %s.%s with value %s is omitted by omitempty but kept by omitzero", location, owner, field.goName, encoded)
+	}
+	return fmt.Errorf("%s: field %s.%s with value %s is kept by omitempty but omitted by omitzero", location, owner, field.goName, encoded)
+}
+
+// isEmptyValue reports whether encoding/json's omitempty option omits v.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isZeroValue reports whether encoding/json's omitzero option omits v: the IsZero method is
+// used when v has one, and otherwise v must be its type's zero value.
+func isZeroValue(v reflect.Value) bool {
+	if v.Type().Implements(isZeroerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return true
+		}
+		return v.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(isZeroerType) {
+		return v.Addr().Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	return v.IsZero()
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but returns false instead of panicking when
+// the field is inside a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,52 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type omitStruct struct {
+	Name    string            `json:"name,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+	Created time.Time         `json:"created,omitempty"`
+	Updated time.Time         `json:"updated,omitzero"`
+	Address omitAddress       `json:"address,omitzero"`
+	Counts  map[string]int    `json:"counts,omitzero"`
+	Items   []omitItem        `json:"items"`
+	Extra   map[string]string `json:"extra,omitempty"`
+}
+
+type omitAddress struct {
+	Line1 string `json:"line1"`
+}
+
+type omitItem struct {
+	Labels []string `json:"labels,omitempty"`
+}
+
+func TestOmitZeroCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"no omit tags", "testdata/complete.json", &receiveStruct{}, nil},
+		{"omit differences", "testdata/omit.json", &omitStruct{}, []error{
+			fmt.Errorf(`tags: field jsonassert.omitStruct.Tags with value [] is omitted by omitempty but kept by omitzero`),
+			fmt.Errorf(`created: field jsonassert.omitStruct.Created with value "0001-01-01T00:00:00Z" is kept by omitempty but omitted by omitzero`),
+			fmt.Errorf(`address: field jsonassert.omitStruct.Address with value {"line1":""} is kept by omitempty but omitted by omitzero`),
+			fmt.Errorf(`counts: field jsonassert.omitStruct.Counts with value {} is omitted by omitempty but kept by omitzero`),
+			fmt.Errorf(`items[0].labels: field jsonassert.omitItem.Labels with value [] is omitted by omitempty but kept by omitzero`),
+		}},
+		{"wrong result type", "testdata/omit.json", &jsonComplete, []error{fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got *string")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			OmitZeroCheck(fakeT, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
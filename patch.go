@@ -0,0 +1,122 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Patch returns an RFC 6902 JSON Patch that transforms json1 into json2, for tools that apply
+// the differences, such as a script that updates fixtures:
+//   [{"op": "replace", "path": "/claims/0/paidAmount", "value": 12}]
+// Unlike the comparison, the patch describes every difference in the text, so null and a
+// missing key, or 1 and 1.0, differ. Keys are added and removed in sorted order, and array
+// elements are replaced by index, with elements removed from the end or added at it as the
+// lengths require. The documents may hold any JSON value. The values in the patch aren't
+// masked, since the patch must reproduce them.
+func Patch(json1, json2 []byte) ([]byte, error) {
+	return NewComparer().Patch(json1, json2)
+}
+
+// Patch is like the package-level Patch, but reads the JSON using the options of the Comparer
+// and leaves the values at ignored paths as they are in json1.
+func (c *Comparer) Patch(json1, json2 []byte) ([]byte, error) {
+	value1, err := c.getJSONValue(json1)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling json1: %v", err)
+	}
+	value2, err := c.getJSONValue(json2)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling json2: %v", err)
+	}
+	ops := []patchOp{}
+	if err := c.patch(&ops, "", "", value1, value2); err != nil {
+		return nil, err
+	}
+	return json.Marshal(ops)
+}
+
+// patchOp is an operation in a JSON Patch. Value is omitted from remove operations.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patch appends the operations that transform value1 into value2 to ops. location is the path
+// of the values in dot notation, which isn't ignored, and pointer is the same path as a JSON
+// Pointer.
+func (c *Comparer) patch(ops *[]patchOp, location, pointer string, value1, value2 interface{}) error {
+	map1, isMap1 := value1.(map[string]interface{})
+	map2, isMap2 := value2.(map[string]interface{})
+	if isMap1 && isMap2 {
+		for _, key := range mergedKeys(map1, map2) {
+			keyLocation, keyPointer := getLocation(location, key), pointer+"/"+pointerEscaper.Replace(key)
+			if c.ruleFor(keyLocation).ignore {
+				continue
+			}
+			v1, ok1 := map1[key]
+			v2, ok2 := map2[key]
+			var err error
+			switch {
+			case !ok2:
+				err = addPatchOp(ops, "remove", keyLocation, keyPointer, nil)
+			case !ok1:
+				err = addPatchOp(ops, "add", keyLocation, keyPointer, v2)
+			default:
+				err = c.patch(ops, keyLocation, keyPointer, v1, v2)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	slice1, isSlice1 := value1.([]interface{})
+	slice2, isSlice2 := value2.([]interface{})
+	if isSlice1 && isSlice2 {
+		var removed []string
+		for i := 0; i < len(slice1) || i < len(slice2); i++ {
+			indexLocation, indexPointer := fmt.Sprintf("%s[%d]", location, i), fmt.Sprintf("%s/%d", pointer, i)
+			if c.ruleFor(indexLocation).ignore {
+				continue
+			}
+			var err error
+			switch {
+			case i >= len(slice2):
+				removed = append(removed, indexPointer)
+			case i >= len(slice1):
+				err = addPatchOp(ops, "add", indexLocation, indexPointer, slice2[i])
+			default:
+				err = c.patch(ops, indexLocation, indexPointer, slice1[i], slice2[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// elements are removed from the end, so that removing one doesn't move the others
+		for i := len(removed) - 1; i >= 0; i-- {
+			*ops = append(*ops, patchOp{Op: "remove", Path: removed[i]})
+		}
+		return nil
+	}
+	if reflect.DeepEqual(value1, value2) {
+		return nil
+	}
+	return addPatchOp(ops, "replace", location, pointer, value2)
+}
+
+// addPatchOp appends an operation on the value at location to ops. value is nil for remove
+// operations.
+func addPatchOp(ops *[]patchOp, op, location, pointer string, value interface{}) error {
+	o := patchOp{Op: op, Path: pointer}
+	if op != "remove" {
+		encoded, err := json.Marshal(encodableNumbers(value))
+		if err != nil {
+			return fmt.Errorf("error encoding the value at %s: %v", location, err)
+		}
+		o.Value = encoded
+	}
+	*ops = append(*ops, o)
+	return nil
+}
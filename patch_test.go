@@ -0,0 +1,45 @@
+package jsonassert
+
+import (
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		json1         string
+		json2         string
+		expected      string
+		expectedError string
+	}{
+		{"equal", nil, `{"a": [1, {"b": null}]}`, `{"a": [1, {"b": null}]}`, `[]`, ""},
+		{"keys", nil, `{"a": 1, "b": {"c": "x"}, "d": null}`, `{"b": {"c": "y", "e": [1]}, "d": 1.50, "f": null}`,
+			`[{"op":"remove","path":"/a"},{"op":"replace","path":"/b/c","value":"y"},{"op":"add","path":"/b/e","value":[1]},{"op":"replace","path":"/d","value":1.50},{"op":"add","path":"/f","value":null}]`, ""},
+		{"escaped keys", nil, `{"a/b": {"c~d": 1}}`, `{"a/b": {"c~d": 2}}`, `[{"op":"replace","path":"/a~1b/c~0d","value":2}]`, ""},
+		{"shorter array", nil, `[1, 2, 3, 4]`, `[1, 5]`, `[{"op":"replace","path":"/1","value":5},{"op":"remove","path":"/3"},{"op":"remove","path":"/2"}]`, ""},
+		{"longer array", nil, `{"a": []}`, `{"a": [1, {"b": 2}]}`, `[{"op":"add","path":"/a/0","value":1},{"op":"add","path":"/a/1","value":{"b":2}}]`, ""},
+		{"types", nil, `{"a": {"b": 1}, "c": [1]}`, `{"a": [1], "c": "x"}`, `[{"op":"replace","path":"/a","value":[1]},{"op":"replace","path":"/c","value":"x"}]`, ""},
+		{"whole document", nil, `1`, `"x"`, `[{"op":"replace","path":"","value":"x"}]`, ""},
+		{"ignored", []Option{WithIgnorePaths("meta", "items[*].at")}, `{"meta": 1, "items": [{"at": 1, "n": 1}]}`, `{"items": [{"at": 2, "n": 2}]}`, `[{"op":"replace","path":"/items/0/n","value":2}]`, ""},
+		{"non-finite", []Option{AllowNonFinite()}, `{"a": 1}`, `{"a": NaN}`, `[{"op":"replace","path":"/a","value":"NaN"}]`, ""},
+		{"invalid", nil, `{`, `{}`, "", "error unmarshalling json1: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewComparer(tt.opts...).Patch([]byte(tt.json1), []byte(tt.json2))
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("error = %v, want %s", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(patch) != tt.expected {
+				t.Errorf("patch mismatch. want:\n%s\ngot:\n%s", tt.expected, patch)
+			}
+		})
+	}
+}
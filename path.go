@@ -0,0 +1,158 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PathFormat is a notation for the paths of mismatches, set by WithPathFormat.
+type PathFormat string
+
+const (
+	// PathDot writes paths like items[0].name, the notation that options such as
+	// WithIgnorePaths take. Keys that are empty or contain '.', '[', ']' or '"' are quoted in
+	// brackets, as in meta["x.y"], so that paths are never ambiguous. It is the default.
+	PathDot PathFormat = "dot"
+	// PathPointer writes paths as RFC 6901 JSON Pointers, like /items/0/name, with "~" and "/"
+	// in keys written as "~0" and "~1".
+	PathPointer PathFormat = "pointer"
+	// PathJSONPath writes paths as RFC 9535 JSONPath queries, like $.items[0].name, with keys
+	// that aren't identifiers quoted in brackets, as in $["x.y"].
+	PathJSONPath PathFormat = "jsonpath"
+)
+
+// WithPathFormat writes the paths of mismatches, and the messages that start with them, in the
+// given notation, for tools that locate values by JSON Pointer or JSONPath:
+//   /claims/0/paidAmount mismatch. 10 vs. 12
+// Options still take paths in dot notation, and Walk and WithTrace still use it. WithPathFormat
+// panics if the format is unknown.
+func WithPathFormat(format PathFormat) Option {
+	switch format {
+	case PathDot, PathPointer, PathJSONPath:
+	default:
+		panic(fmt.Sprintf("unknown path format %q", format))
+	}
+	return func(c *Comparer) {
+		c.pathFormat = format
+	}
+}
+
+// pathSegment is a key or array index in a path. The key of an index holds its digits.
+type pathSegment struct {
+	key   string
+	index bool
+}
+
+// formatPath writes location, which is in dot notation, in the Comparer's path format.
+func (c *Comparer) formatPath(location string) string {
+	if c.pathFormat == "" || c.pathFormat == PathDot {
+		return location
+	}
+	return formatSegments(splitLocation(location), c.pathFormat)
+}
+
+// quoteKey writes key as it follows another key in dot notation, quoted in brackets if it would
+// be ambiguous otherwise.
+func quoteKey(key string) string {
+	if key == "" || strings.ContainsAny(key, `.[]"`) {
+		return "[" + strconv.Quote(key) + "]"
+	}
+	return "." + key
+}
+
+// splitLocation splits a path in dot notation, or the part of a JSONPath query after the "$",
+// into its keys and indexes.
+func splitLocation(location string) []pathSegment {
+	var segments []pathSegment
+	for i := 0; i < len(location); {
+		rest := location[i:]
+		switch {
+		case rest[0] == '.':
+			i++
+		case strings.HasPrefix(rest, `["`):
+			quoted, err := strconv.QuotedPrefix(rest[1:])
+			if err != nil {
+				return append(segments, pathSegment{key: rest})
+			}
+			key, _ := strconv.Unquote(quoted)
+			segments = append(segments, pathSegment{key: key})
+			i += len(quoted) + 2
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return append(segments, pathSegment{key: rest})
+			}
+			segments = append(segments, pathSegment{key: rest[1:end], index: true})
+			i += end + 1
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			i += end
+		}
+	}
+	return segments
+}
+
+// splitPath splits a path written in format into its keys and indexes. The indexes in a JSON
+// Pointer can't be told apart from keys, so keys that are all digits count as indexes.
+func splitPath(path string, format PathFormat) []pathSegment {
+	switch format {
+	case PathPointer:
+		if path == "" {
+			return nil
+		}
+		var segments []pathSegment
+		for _, key := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+			key = pointerUnescaper.Replace(key)
+			_, err := strconv.ParseUint(key, 10, 64)
+			segments = append(segments, pathSegment{key: key, index: err == nil})
+		}
+		return segments
+	case PathJSONPath:
+		return splitLocation(strings.TrimPrefix(path, "$"))
+	}
+	return splitLocation(path)
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// formatSegments writes segments as a path in format.
+func formatSegments(segments []pathSegment, format PathFormat) string {
+	var sb strings.Builder
+	if format == PathJSONPath {
+		sb.WriteString("$")
+	}
+	for _, segment := range segments {
+		switch {
+		case format == PathPointer:
+			sb.WriteString("/" + pointerEscaper.Replace(segment.key))
+		case segment.index:
+			sb.WriteString("[" + segment.key + "]")
+		case format == PathJSONPath && !isIdentifier(segment.key):
+			sb.WriteString("[" + strconv.Quote(segment.key) + "]")
+		case sb.Len() == 0:
+			sb.WriteString(strings.TrimPrefix(quoteKey(segment.key), "."))
+		default:
+			sb.WriteString(quoteKey(segment.key))
+		}
+	}
+	return sb.String()
+}
+
+// isIdentifier reports whether key can be written after a "." in a JSONPath query.
+func isIdentifier(key string) bool {
+	for i, r := range key {
+		if r != '_' && !unicode.IsLetter(r) && r < 0x80 && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return key != ""
+}
@@ -0,0 +1,75 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWithPathFormat(t *testing.T) {
+	json1 := `{"items": [{"name": "a"}], "a.b": {"c~d/e": 1}, "": 1, "x-y": 1}`
+	json2 := `{"items": [{"name": "b"}], "a.b": {"c~d/e": 2}, "": 2, "x-y": 2}`
+	tests := []struct {
+		name           string
+		format         PathFormat
+		expectedErrors []error
+	}{
+		{"dot", PathDot, []error{
+			fmt.Errorf(`[""] mismatch. 1 vs. 2`),
+			fmt.Errorf(`["a.b"].c~d/e mismatch. 1 vs. 2`),
+			fmt.Errorf(`items[0].name mismatch. "a" vs. "b"`),
+			fmt.Errorf(`x-y mismatch. 1 vs. 2`),
+		}},
+		{"pointer", PathPointer, []error{
+			fmt.Errorf(`/ mismatch. 1 vs. 2`),
+			fmt.Errorf(`/a.b/c~0d~1e mismatch. 1 vs. 2`),
+			fmt.Errorf(`/items/0/name mismatch. "a" vs. "b"`),
+			fmt.Errorf(`/x-y mismatch. 1 vs. 2`),
+		}},
+		{"jsonpath", PathJSONPath, []error{
+			fmt.Errorf(`$[""] mismatch. 1 vs. 2`),
+			fmt.Errorf(`$["a.b"]["c~d/e"] mismatch. 1 vs. 2`),
+			fmt.Errorf(`$.items[0].name mismatch. "a" vs. "b"`),
+			fmt.Errorf(`$["x-y"] mismatch. 1 vs. 2`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(WithPathFormat(tt.format)).EqualMap([]byte(json1), []byte(json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithPathFormatRules(t *testing.T) {
+	c := NewComparer(WithPathFormat(PathPointer), WithIgnorePaths(`["a.b"]`, "items[*].id"))
+	errs := c.EqualMap([]byte(`{"a.b": 1, "a": {"b": 1}, "items": [{"id": 1}]}`), []byte(`{"a.b": 2, "a": {"b": 2}, "items": [{"id": 2}]}`))
+	checkErrors(t, []error{fmt.Errorf("/a/b mismatch. 1 vs. 2")}, errs)
+}
+
+func TestTopLevel(t *testing.T) {
+	tests := []struct {
+		path          string
+		format        PathFormat
+		expected      string
+		expectedIndex bool
+	}{
+		{"claims[0].amount", PathDot, "claims", false},
+		{"[2].id", PathDot, "[2]", true},
+		{`["a.b"].c`, PathDot, `["a.b"]`, false},
+		{`["a]b"][0]`, PathDot, `["a]b"]`, false},
+		{"/claims/0/amount", PathPointer, "/claims", false},
+		{"/2/id", PathPointer, "/2", true},
+		{"/a~1b", PathPointer, "/a~1b", false},
+		{"$.claims[0].amount", PathJSONPath, "$.claims", false},
+		{`$["a.b"].c`, PathJSONPath, `$["a.b"]`, false},
+		{"$[2].id", PathJSONPath, "$[2]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			top, index := topLevel(tt.path, tt.format)
+			if top != tt.expected || index != tt.expectedIndex {
+				t.Errorf("topLevel(%q) = %q, %v, want %q, %v", tt.path, top, index, tt.expected, tt.expectedIndex)
+			}
+		})
+	}
+}
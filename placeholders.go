@@ -0,0 +1,136 @@
+package jsonassert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// uuidRegexp matches UUIDs in their canonical, hyphenated form.
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// builtinPlaceholders are the placeholders enabled by WithPlaceholders.
+var builtinPlaceholders = map[string]Matcher{
+	"<<PRESENCE>>": placeholderMatcher{"any value", func(value interface{}) bool {
+		return value != nil
+	}},
+	"<<UUID>>": placeholderMatcher{"a UUID", func(value interface{}) bool {
+		s, ok := value.(string)
+		return ok && uuidRegexp.MatchString(s)
+	}},
+	"<<TIMESTAMP>>": placeholderMatcher{"an RFC 3339 timestamp", func(value interface{}) bool {
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	}},
+}
+
+// WithPlaceholders lets json1 hold placeholder tokens in place of values that are generated
+// anew for each response, so one golden file can cover them. A string in json1 that is exactly
+// a token matches the value of json2 at the same path instead of being compared with it:
+//   <<PRESENCE>>    any value that isn't null or missing
+//   <<UUID>>        a string holding a UUID, such as "3f2b8c1e-9d4a-4e6f-8a7b-1c2d3e4f5a6b"
+//   <<TIMESTAMP>>   a string holding an RFC 3339 timestamp, such as "2024-03-01T12:00:00Z"
+//   <<REGEX:expr>>  a string matching the regular expression expr, such as <<REGEX:^ord-\d+$>>
+// A regular expression that can't be compiled is reported with each value it is compared with.
+// Tokens in json2 are compared as strings. Add tokens of your own with WithPlaceholder.
+func WithPlaceholders() Option {
+	return func(c *Comparer) {
+		c.addPlaceholders(builtinPlaceholders)
+		c.regexpPlaceholders = &sync.Map{}
+	}
+}
+
+// WithPlaceholder lets json1 hold token in place of any value that m matches, as described on
+// WithPlaceholders:
+//   jsonassert.WithPlaceholder("<<ORDER_ID>>", jsonassert.MatchRegexp(`^ord-\d+$`))
+// It replaces a placeholder with the same token, including the built-in ones.
+func WithPlaceholder(token string, m Matcher) Option {
+	return func(c *Comparer) {
+		c.addPlaceholders(map[string]Matcher{token: m})
+	}
+}
+
+// addPlaceholders adds placeholders to a copy of the Comparer's placeholders, so that copies
+// made by With don't share them.
+func (c *Comparer) addPlaceholders(placeholders map[string]Matcher) {
+	merged := make(map[string]Matcher, len(c.placeholders)+len(placeholders))
+	for token, m := range c.placeholders {
+		merged[token] = m
+	}
+	for token, m := range placeholders {
+		merged[token] = m
+	}
+	c.placeholders = merged
+}
+
+// placeholder returns the token value1 holds and its Matcher, if it is a placeholder.
+func (c *Comparer) placeholder(value1 interface{}) (string, Matcher, bool) {
+	token, ok := value1.(string)
+	if !ok {
+		return "", nil, false
+	}
+	if m, ok := c.placeholders[token]; ok {
+		return token, m, true
+	}
+	if c.regexpPlaceholders == nil || len(token) < len(regexpPrefix+">>") || !strings.HasPrefix(token, regexpPrefix) || !strings.HasSuffix(token, ">>") {
+		return "", nil, false
+	}
+	if m, ok := c.regexpPlaceholders.Load(token); ok {
+		return token, m.(Matcher), true
+	}
+	var m Matcher
+	re, err := regexp.Compile(token[len(regexpPrefix) : len(token)-len(">>")])
+	if err != nil {
+		m = invalidRegexpMatcher{err}
+	} else {
+		m = regexpMatcher{re}
+	}
+	c.regexpPlaceholders.Store(token, m)
+	return token, m, true
+}
+
+// regexpPrefix starts the placeholders that match a regular expression.
+const regexpPrefix = "<<REGEX:"
+
+// invalidRegexpMatcher is the Matcher for a regular expression placeholder that can't be
+// compiled, which matches nothing, so that the error is reported for each value it is compared
+// with.
+type invalidRegexpMatcher struct {
+	err error
+}
+
+func (m invalidRegexpMatcher) Match(value interface{}) bool {
+	return false
+}
+
+func (m invalidRegexpMatcher) String() string {
+	return fmt.Sprintf("invalid regexp: %v", m.err)
+}
+
+// matchPlaceholder matches value2 against the placeholder token in json1.
+func (c *Comparer) matchPlaceholder(location, token string, m Matcher, value2 interface{}) []error {
+	if m.Match(value2) {
+		return nil
+	}
+	return []error{c.mismatch(KindPattern, location, token, value2, fmt.Sprintf("%s (%s)", token, m), c.quoteValue(location, value2))}
+}
+
+// placeholderMatcher is a Matcher for the built-in placeholders.
+type placeholderMatcher struct {
+	description string
+	match       func(value interface{}) bool
+}
+
+func (m placeholderMatcher) Match(value interface{}) bool {
+	return m.match(value)
+}
+
+func (m placeholderMatcher) String() string {
+	return m.description
+}
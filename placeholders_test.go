@@ -0,0 +1,76 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"off by default", nil, `{"id": "<<UUID>>"}`, `{"id": "3f2b8c1e-9d4a-4e6f-8a7b-1c2d3e4f5a6b"}`, []error{
+			fmt.Errorf(`id mismatch. "<<UUID>>" vs. "3f2b8c1e-9d4a-4e6f-8a7b-1c2d3e4f5a6b"`),
+		}},
+		{"matched", []Option{WithPlaceholders()},
+			`{"id": "<<UUID>>", "createdAt": "<<TIMESTAMP>>", "items": [{"sku": "<<PRESENCE>>"}, {"sku": "<<PRESENCE>>"}]}`,
+			`{"id": "3F2B8C1E-9D4A-4E6F-8A7B-1C2D3E4F5A6B", "createdAt": "2024-06-01T12:00:00.5-05:00", "items": [{"sku": 0}, {"sku": {"a": 1}}]}`, nil},
+		{"not matched", []Option{WithPlaceholders()},
+			`{"id": "<<UUID>>", "createdAt": "<<TIMESTAMP>>", "items": [{"sku": "<<PRESENCE>>"}, {"sku": "<<PRESENCE>>"}]}`,
+			`{"id": "3f2b8c1e", "createdAt": "2024-06-01", "items": [{"sku": null}, {}]}`, []error{
+				fmt.Errorf(`createdAt mismatch. "2024-06-01" doesn't match <<TIMESTAMP>> (an RFC 3339 timestamp)`),
+				fmt.Errorf(`id mismatch. "3f2b8c1e" doesn't match <<UUID>> (a UUID)`),
+				fmt.Errorf(`items[0].sku mismatch. <nil> doesn't match <<PRESENCE>> (any value)`),
+				fmt.Errorf(`items[1].sku mismatch. <nil> doesn't match <<PRESENCE>> (any value)`),
+			}},
+		{"missing", []Option{WithPlaceholders()}, `{"id": "<<UUID>>"}`, `{}`, []error{
+			fmt.Errorf(`id mismatch. <nil> doesn't match <<UUID>> (a UUID)`),
+		}},
+		{"only in json1", []Option{WithPlaceholders()}, `{"id": "3f2b8c1e-9d4a-4e6f-8a7b-1c2d3e4f5a6b"}`, `{"id": "<<UUID>>"}`, []error{
+			fmt.Errorf(`id mismatch. "3f2b8c1e-9d4a-4e6f-8a7b-1c2d3e4f5a6b" vs. "<<UUID>>"`),
+		}},
+		{"custom", []Option{WithPlaceholder("<<ORDER_ID>>", MatchRegexp(`^ord-\d+$`))}, `{"a": "<<ORDER_ID>>", "b": "<<ORDER_ID>>", "c": "<<UUID>>"}`, `{"a": "ord-1", "b": "x", "c": "<<UUID>>"}`, []error{
+			fmt.Errorf(`b mismatch. "x" doesn't match <<ORDER_ID>> (regexp "^ord-\\d+$")`),
+		}},
+		{"replaced", []Option{WithPlaceholders(), WithPlaceholder("<<UUID>>", MatchRegexp(`^[0-9a-f-]+$`))}, `{"a": "<<UUID>>", "b": "<<PRESENCE>>"}`, `{"a": "abc", "b": 1}`, nil},
+		{"regexp", []Option{WithPlaceholders()}, `{"a": "<<REGEX:^ord-\\d+$>>", "b": "<<REGEX:^ord-\\d+$>>", "c": "<<REGEX:>>", "d": "<<REGEX:(>>"}`, `{"a": "ord-12", "b": "ord-x", "c": "", "d": "("}`, []error{
+			fmt.Errorf(`b mismatch. "ord-x" doesn't match <<REGEX:^ord-\d+$>> (regexp "^ord-\\d+$")`),
+			fmt.Errorf("d mismatch. \"(\" doesn't match <<REGEX:(>> (invalid regexp: error parsing regexp: missing closing ): `(`)"),
+		}},
+		{"regexp off", []Option{WithPlaceholder("<<ID>>", MatchRegexp("^x$"))}, `{"a": "<<REGEX:^x$>>"}`, `{"a": "x"}`, []error{
+			fmt.Errorf(`a mismatch. "<<REGEX:^x$>>" vs. "x"`),
+		}},
+		{"masked", []Option{WithPlaceholders(), MaskPHI("ssn")}, `{"ssn": "<<UUID>>"}`, `{"ssn": "123-45-6789"}`, []error{
+			fmt.Errorf(`ssn mismatch. "***-**-****" doesn't match <<UUID>> (a UUID)`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithPlaceholderCopies(t *testing.T) {
+	c := NewComparer(WithPlaceholders())
+	c.With(WithPlaceholder("<<PRESENCE>>", MatchRegexp("^x$")))
+	checkErrors(t, nil, c.EqualMap([]byte(`{"a": "<<PRESENCE>>"}`), []byte(`{"a": 1}`)))
+}
+
+func TestRegexpPlaceholderCache(t *testing.T) {
+	c := NewComparer(WithPlaceholders())
+	_, m1, _ := c.placeholder("<<REGEX:^cached-\\d+$>>")
+	_, m2, _ := c.With().placeholder("<<REGEX:^cached-\\d+$>>")
+	if m1.(regexpMatcher).re != m2.(regexpMatcher).re {
+		t.Errorf("expected the regexp to be compiled once")
+	}
+	_, m3, _ := NewComparer(WithPlaceholders()).placeholder("<<REGEX:^cached-\\d+$>>")
+	if m1.(regexpMatcher).re == m3.(regexpMatcher).re {
+		t.Errorf("expected each Comparer to compile its own regexps")
+	}
+}
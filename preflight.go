@@ -0,0 +1,184 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// preflightErrors walks the value that StructCheck decodes into, and its type, and returns the
+// problems that can be found before decoding. These are problems encoding/json doesn't report, or reports with a
+// message that doesn't point at the field responsible.
+func preflightErrors(v reflect.Value) []error {
+	var errors []error
+	visitTypes(v.Type(), typeContext{}, map[reflect.Type]bool{}, func(t reflect.Type, ctx typeContext) {
+		switch t.Kind() {
+		case reflect.Struct:
+			errors = append(errors, ambiguousFieldErrors(t)...)
+		case reflect.Map:
+			if err := mapKeyError(t, ctx); err != nil {
+				errors = append(errors, err)
+			}
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+			errors = append(errors, fmt.Errorf("unsupported type in %s: encoding/json can't encode or decode %s values", ctx.describe(t), t.Kind()))
+		}
+	})
+	return append(errors, cycleErrors("", v, map[uintptr]bool{})...)
+}
+
+// typeContext is where a type was reached: the path from the root type using JSON names, with
+// "[]" for elements and "[key]" for map values, and the nearest struct field, if any.
+type typeContext struct {
+	path  string
+	owner reflect.Type
+	field *structField
+}
+
+func (ctx typeContext) describe(t reflect.Type) string {
+	description := t.String()
+	if ctx.path != "" {
+		description += " at " + ctx.path
+	}
+	if ctx.field != nil {
+		description += fmt.Sprintf(" (field %s.%s)", ctx.owner, ctx.field.goName)
+	}
+	return description
+}
+
+// visitTypes calls fn for t and each type reachable from it through the fields encoding/json
+// uses, along with the nearest struct field the type was reached through. Each type is visited
+// once.
+func visitTypes(t reflect.Type, ctx typeContext, visited map[reflect.Type]bool, fn func(reflect.Type, typeContext)) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	if implementsMarshaler(t) {
+		return // custom marshalers decide how their values are encoded
+	}
+	fn(t, ctx)
+	switch t.Kind() {
+	case reflect.Ptr:
+		visitTypes(t.Elem(), ctx, visited, fn)
+	case reflect.Slice, reflect.Array:
+		elemCtx := ctx
+		elemCtx.path += "[]"
+		visitTypes(t.Elem(), elemCtx, visited, fn)
+	case reflect.Map:
+		elemCtx := ctx
+		elemCtx.path += "[key]"
+		visitTypes(t.Elem(), elemCtx, visited, fn)
+	case reflect.Struct:
+		for _, field := range cachedTypeFields(t) {
+			field := field
+			visitTypes(field.typ, typeContext{path: getLocation(ctx.path, field.name), owner: t, field: &field}, visited, fn)
+		}
+	}
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return pt.Implements(marshalerType) || pt.Implements(textMarshalerType) || implementsUnmarshaler(t)
+}
+
+// cycleErrors returns an error for each pointer in v that points back to a value containing it.
+// encoding/json can't encode such a value. Decoding never creates cycles, but a result that
+// already holds one keeps it for any field the JSON doesn't set.
+func cycleErrors(location string, v reflect.Value, active map[uintptr]bool) []error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if active[ptr] {
+				return []error{fmt.Errorf("pointer cycle at %s: encoding/json can't encode values that contain themselves", location)}
+			}
+			active[ptr] = true
+			defer delete(active, ptr)
+		}
+		return cycleErrors(location, v.Elem(), active)
+	case reflect.Struct:
+		if implementsMarshaler(v.Type()) {
+			return nil
+		}
+		var errors []error
+		for _, field := range cachedTypeFields(v.Type()) {
+			if fv, ok := fieldByIndex(v, field.index); ok {
+				errors = append(errors, cycleErrors(getLocation(location, field.name), fv, active)...)
+			}
+		}
+		return errors
+	case reflect.Slice, reflect.Array:
+		var errors []error
+		for i := 0; i < v.Len(); i++ {
+			errors = append(errors, cycleErrors(fmt.Sprintf("%s[%d]", location, i), v.Index(i), active)...)
+		}
+		return errors
+	}
+	return nil
+}
+
+// mapKeyError returns an error if encoding/json can't use the key type of the map type t as
+// an object key. Keys must be strings, integers, or implement both encoding.TextMarshaler and
+// encoding.TextUnmarshaler.
+func mapKeyError(t reflect.Type, ctx typeContext) error {
+	key := t.Key()
+	switch key.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return nil
+	}
+	if key.Implements(textMarshalerType) && reflect.PtrTo(key).Implements(textUnmarshalerType) {
+		return nil
+	}
+	return fmt.Errorf("invalid map key type in %s: %s can't be used as a JSON object key; use a string, an integer, or a type implementing encoding.TextMarshaler and encoding.TextUnmarshaler", ctx.describe(t), key)
+}
+
+// ambiguousFieldErrors returns an error for each JSON name that is promoted from more than one
+// embedded struct at the same depth without a single tagged field to break the tie. encoding/json
+// silently ignores all of the conflicting fields.
+func ambiguousFieldErrors(t reflect.Type) []error {
+	byName := map[string][]structField{}
+	var names []string
+	for _, field := range candidateFields(t) {
+		if _, ok := byName[field.name]; !ok {
+			names = append(names, field.name)
+		}
+		byName[field.name] = append(byName[field.name], field)
+	}
+	var errors []error
+	for _, name := range names {
+		fields := byName[name]
+		if len(fields) < 2 {
+			continue
+		}
+		if _, ok := dominantField(fields); ok {
+			continue
+		}
+		depth := len(fields[0].index)
+		var paths []string
+		for _, field := range fields {
+			if len(field.index) == depth {
+				paths = append(paths, fieldPath(t, field.index))
+			}
+		}
+		errors = append(errors, fmt.Errorf("ambiguous field %q in %s: promoted from %s at the same depth, so encoding/json ignores all of them", name, t, strings.Join(paths, " and ")))
+	}
+	return errors
+}
+
+// fieldPath returns the Go selector for the field at index in t, such as "Address.ID".
+func fieldPath(t reflect.Type, index []int) string {
+	var names []string
+	for _, i := range index {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		field := t.Field(i)
+		names = append(names, field.Name)
+		t = field.Type
+	}
+	return strings.Join(names, ".")
+}
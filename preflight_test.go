@@ -0,0 +1,108 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Billing struct {
+	ID   string
+	Name string
+}
+
+type Shipping struct {
+	ID   string
+	Name string `json:"Name"`
+}
+
+type conflictStruct struct {
+	Billing
+	*Shipping
+}
+
+type resolvedStruct struct {
+	Billing
+	Shipping
+	ID string
+}
+
+type textKey struct{ a, b string }
+
+func (k textKey) MarshalText() ([]byte, error) { return []byte(k.a + "/" + k.b), nil }
+func (k *textKey) UnmarshalText(text []byte) error {
+	k.a, k.b, _ = strings.Cut(string(text), "/")
+	return nil
+}
+
+type mapKeysStruct struct {
+	ByName  map[string]int       `json:"byName"`
+	ByID    map[int64]int        `json:"byId"`
+	ByText  map[textKey]int      `json:"byText"`
+	ByScore []map[float64]string `json:"byScore"`
+}
+
+type unsupportedStruct struct {
+	Items    []unsupportedItem `json:"items"`
+	Callback *func()           `json:"callback"`
+	Point    complex128        `json:"point"`
+}
+
+type unsupportedItem struct {
+	Notify chan int `json:"notify"`
+}
+
+type customChannel chan int
+
+func (c customChannel) MarshalJSON() ([]byte, error) { return []byte("null"), nil }
+
+type customMarshalerStruct struct {
+	Channel customChannel `json:"channel"`
+}
+
+type node struct {
+	Name string `json:"name"`
+	Next *node  `json:"next"`
+}
+
+func cyclicNode() *node {
+	n := &node{Name: "a", Next: &node{Name: "b"}}
+	n.Next.Next = n
+	return n
+}
+
+func TestPreflightErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"no embedding", &receiveStruct{}, nil},
+		{"conflict", &conflictStruct{}, []error{
+			fmt.Errorf(`ambiguous field "ID" in jsonassert.conflictStruct: promoted from Billing.ID and Shipping.ID at the same depth, so encoding/json ignores all of them`),
+		}},
+		{"resolved by depth", &resolvedStruct{}, nil},
+		{"map keys", &mapKeysStruct{}, []error{
+			fmt.Errorf("invalid map key type in map[float64]string at byScore[] (field jsonassert.mapKeysStruct.ByScore): float64 can't be used as a JSON object key; use a string, an integer, or a type implementing encoding.TextMarshaler and encoding.TextUnmarshaler"),
+		}},
+		{"unsupported types", &unsupportedStruct{}, []error{
+			fmt.Errorf("unsupported type in chan int at items[].notify (field jsonassert.unsupportedItem.Notify): encoding/json can't encode or decode chan values"),
+			fmt.Errorf("unsupported type in func() at callback (field jsonassert.unsupportedStruct.Callback): encoding/json can't encode or decode func values"),
+			fmt.Errorf("unsupported type in complex128 at point (field jsonassert.unsupportedStruct.Point): encoding/json can't encode or decode complex128 values"),
+		}},
+		{"custom marshaler", &customMarshalerStruct{}, nil},
+		{"pointer cycle", cyclicNode(), []error{fmt.Errorf("pointer cycle at next.next: encoding/json can't encode values that contain themselves")}},
+		{"map key at root", &map[bool]string{}, []error{
+			fmt.Errorf("invalid map key type in map[bool]string: bool can't be used as a JSON object key; use a string, an integer, or a type implementing encoding.TextMarshaler and encoding.TextUnmarshaler"),
+		}},
+		{"nested in slice", &[]map[string]conflictStruct{}, []error{
+			fmt.Errorf(`ambiguous field "ID" in jsonassert.conflictStruct: promoted from Billing.ID and Shipping.ID at the same depth, so encoding/json ignores all of them`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrors(t, tt.expectedErrors, preflightErrors(reflect.ValueOf(tt.result)))
+		})
+	}
+}
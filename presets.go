@@ -0,0 +1,109 @@
+package jsonassert
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PresetName names a curated set of options that can be applied with Preset.
+type PresetName string
+
+const (
+	// PresetLenient is the default behavior of the package-level functions: nil is equal to the
+	// default value for its data type, so "", 0, false, [] and {} are equal to null or a missing
+	// key. Use it for round trip checks of JSON produced by encoders other than Go's.
+	PresetLenient PresetName = "lenient"
+	// PresetStrict disables the nil and default value equivalences, for contract tests where
+	// "" and null mean different things.
+	PresetStrict PresetName = "strict"
+	// PresetAPICompat checks that json2 is a backwards compatible version of json1: json2 may
+	// add keys, but every key in json1 must still be in json2 with an equal value. It is strict
+	// about nil and default values, since changing one to the other can break clients.
+	PresetAPICompat PresetName = "apicompat"
+	// PresetClaims compares healthcare claim JSON converted from X12 EDI, such as 837 and 835
+	// transactions. It ignores the control numbers, trace numbers and submission timestamps
+	// that change with every submission, compares amounts (keys ending in Amount) as decimals
+	// rounded to 2 places, and pairs the elements of serviceLines arrays by their lineNumber
+	// instead of their order. Amounts and line numbers may be written as numbers or strings.
+	// The rules match keys at any depth. The ignored keys are interchangeControlNumber,
+	// groupControlNumber, transactionSetControlNumber, controlNumber, traceNumber,
+	// interchangeDate, interchangeTime, creationDate, creationTime, submissionDate and
+	// submittedAt.
+	PresetClaims PresetName = "claims"
+)
+
+// claimsIgnorePaths are the volatile values PresetClaims ignores.
+var claimsIgnorePaths = []string{
+	"**.interchangeControlNumber",
+	"**.groupControlNumber",
+	"**.transactionSetControlNumber",
+	"**.controlNumber",
+	"**.traceNumber",
+	"**.interchangeDate",
+	"**.interchangeTime",
+	"**.creationDate",
+	"**.creationTime",
+	"**.submissionDate",
+	"**.submittedAt",
+}
+
+// claimsRules returns the rules of PresetClaims.
+func claimsRules() []pathRule {
+	var rules []pathRule
+	for _, path := range claimsIgnorePaths {
+		rule := newPathRule(path)
+		rule.ignore = true
+		rules = append(rules, rule)
+	}
+	amounts := newPathRule("**.*Amount")
+	amounts.scale, amounts.hasScale, amounts.numericString = 2, true, true
+	serviceLines := newPathRule("**.serviceLines")
+	serviceLines.arrayKey = "lineNumber"
+	lineNumbers := newPathRule("**.serviceLines[*].lineNumber")
+	lineNumbers.numericString = true
+	rules = append(rules, amounts, serviceLines, lineNumbers)
+	for i := range rules {
+		rules[i].fromPreset = true
+	}
+	return rules
+}
+
+// Preset applies a curated set of options. Presets set the behaviors they cover, so a preset
+// applied after Strict or AllowExtraKeys overrides them, while options applied after the preset
+// adjust it, except that rules for a path added after a preset don't override the ones the
+// preset adds for the same path, as described on WithRules. An unknown name applies no options,
+// and the assertions of the Comparer that take a Testing report it as an error. LoadConfig
+// returns the error instead.
+func Preset(name PresetName) Option {
+	opt, err := presetOption(name)
+	if err != nil {
+		return func(c *Comparer) {
+			c.configErr = errors.Join(c.configErr, err)
+		}
+	}
+	return opt
+}
+
+func presetOption(name PresetName) (Option, error) {
+	switch name {
+	case PresetLenient:
+		return func(c *Comparer) {
+			c.strict, c.allowExtraKeys = false, false
+		}, nil
+	case PresetStrict:
+		return func(c *Comparer) {
+			c.strict, c.allowExtraKeys = true, false
+		}, nil
+	case PresetAPICompat:
+		return func(c *Comparer) {
+			c.strict, c.allowExtraKeys = true, true
+		}, nil
+	case PresetClaims:
+		addRules := withPathRules(claimsRules())
+		return func(c *Comparer) {
+			c.strict, c.allowExtraKeys = false, false
+			addRules(c)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown preset %q", name)
+}
@@ -0,0 +1,64 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"lenient", []Option{Strict(), Preset(PresetLenient)}, `{"a": "", "b": 1}`, `{"b": 1, "c": 2}`, []error{fmt.Errorf("c mismatch. <nil> vs. 2")}},
+		{"strict", []Option{Preset(PresetStrict)}, `{"a": "", "b": 1}`, `{"b": 1, "c": 2}`, []error{
+			fmt.Errorf(`a mismatch. "" vs. <nil>`),
+			fmt.Errorf("c mismatch. <nil> vs. 2"),
+		}},
+		{"api compat with additions", []Option{Preset(PresetAPICompat)}, `{"a": "", "b": {"x": 1}}`, `{"a": "", "b": {"x": 1, "y": 2}, "c": 2}`, nil},
+		{"api compat with removals", []Option{Preset(PresetAPICompat)}, `{"a": "", "b": {"x": 1}}`, `{"b": {"y": 2}}`, []error{
+			fmt.Errorf(`a mismatch. "" vs. <nil>`),
+			fmt.Errorf("b.x mismatch. 1 vs. <nil>"),
+		}},
+		{"api compat with type change", []Option{Preset(PresetAPICompat)}, `{"a": 1}`, `{"a": "1"}`, []error{fmt.Errorf(`a mismatch. 1 vs. "1"`)}},
+		{"claims", []Option{Preset(PresetClaims)},
+			`{"interchangeControlNumber": "000000001", "claims": [{"traceNumber": "1", "submittedAt": "2024-06-01T00:00:00Z", "totalChargeAmount": "100.001", "serviceLines": [{"lineNumber": "1", "chargeAmount": 60}, {"lineNumber": "2", "chargeAmount": 40}]}]}`,
+			`{"interchangeControlNumber": "000000002", "claims": [{"traceNumber": "2", "submittedAt": "2024-06-02T00:00:00Z", "totalChargeAmount": 100, "serviceLines": [{"lineNumber": 2, "chargeAmount": "40.00"}, {"lineNumber": 1, "chargeAmount": 60.00}]}]}`,
+			nil},
+		{"claims with differences", []Option{Preset(PresetClaims)},
+			`{"claims": [{"totalChargeAmount": 100, "serviceLines": [{"lineNumber": 1, "chargeAmount": 60}, {"lineNumber": 2, "chargeAmount": 40}]}]}`,
+			`{"claims": [{"totalChargeAmount": 100.01, "serviceLines": [{"lineNumber": 3, "chargeAmount": 40}, {"lineNumber": 1, "chargeAmount": 61}]}]}`,
+			[]error{
+				fmt.Errorf("claims[0].serviceLines[0].chargeAmount mismatch. 60 vs. 61"),
+				fmt.Errorf("claims[0].serviceLines[1] mismatch. map[chargeAmount:40 lineNumber:2] vs. <nil>"),
+				fmt.Errorf("claims[0].serviceLines[0] mismatch. <nil> vs. map[chargeAmount:40 lineNumber:3]"),
+				fmt.Errorf("claims[0].totalChargeAmount mismatch. 100 vs. 100.01"),
+			}},
+		{"options after a preset adjust it", []Option{Preset(PresetAPICompat), WithIgnorePaths("a")}, `{"a": 1}`, `{}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestPresetUnknown(t *testing.T) {
+	c := NewComparer(Preset("bogus"), Strict())
+	if !c.strict {
+		t.Errorf("expected the options after the unknown preset to be applied")
+	}
+	fakeT := &fakeTester{}
+	c.RequireEqualMap(fakeT, []byte(`{"a": ""}`), []byte(`{"a": ""}`))
+	checkErrors(t, []error{fmt.Errorf(`unknown preset "bogus"`)}, fakeT.errors)
+}
+
+func TestPresetClaimsLint(t *testing.T) {
+	c := NewComparer(Preset(PresetClaims), WithIgnorePaths("bogus"))
+	c.EqualMap([]byte(`{"a": 1}`), []byte(`{"a": 1}`))
+	checkErrors(t, []error{fmt.Errorf("rule for bogus matched no path")}, c.LintRules())
+}
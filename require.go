@@ -0,0 +1,130 @@
+package jsonassert
+
+// RequireEqualMap reports the differences between json1 and json2, as returned by EqualMap, and
+// stops the test if there are any. The Require and Must variants stop the test if t is a
+// Fataler, as *testing.T is, so that later assertions don't run against data that is already
+// known to be bad, and otherwise only report the differences.
+func RequireEqualMap(t Testing, json1, json2 []byte) {
+	t.Helper()
+	NewComparer().RequireEqualMap(t, json1, json2)
+}
+
+// RequireEqualMap is like the package-level RequireEqualMap, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) RequireEqualMap(t Testing, json1, json2 []byte) {
+	t.Helper()
+	c.require(t, json1, json2, c.EqualMap(json1, json2))
+}
+
+// RequireEqualSlice reports the differences between json1 and json2, as returned by EqualSlice,
+// and stops the test if there are any.
+func RequireEqualSlice(t Testing, json1, json2 []byte) {
+	t.Helper()
+	NewComparer().RequireEqualSlice(t, json1, json2)
+}
+
+// RequireEqualSlice is like the package-level RequireEqualSlice, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) RequireEqualSlice(t Testing, json1, json2 []byte) {
+	t.Helper()
+	c.require(t, json1, json2, c.EqualSlice(json1, json2))
+}
+
+func (c *Comparer) require(t Testing, json1, json2 []byte, errors []error) {
+	t.Helper()
+	c.reportConfigError(t)
+	c.notifyErrors(t, "json2", errors)
+	c.notifyDiff(t, json1, json2, errors)
+	if len(errors) > 0 {
+		failNow(t)
+	}
+}
+
+// MustStructCheck is like StructCheck, but stops the test if the check fails.
+func MustStructCheck(t Testing, filename string, result interface{}) {
+	t.Helper()
+	NewComparer().MustStructCheck(t, filename, result)
+}
+
+// MustStructCheck is like the package-level MustStructCheck, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) MustStructCheck(t Testing, filename string, result interface{}) {
+	t.Helper()
+	recorder := &failureRecorder{Testing: t}
+	c.StructCheck(recorder, filename, result)
+	if recorder.failed {
+		failNow(t)
+	}
+}
+
+// failureRecorder records whether anything was reported to the Testing it wraps.
+type failureRecorder struct {
+	Testing
+	failed bool
+}
+
+func (r *failureRecorder) Error(args ...interface{}) {
+	r.Helper()
+	r.failed = true
+	r.Testing.Error(args...)
+}
+
+func (r *failureRecorder) Errorf(format string, args ...interface{}) {
+	r.Helper()
+	r.failed = true
+	r.Testing.Errorf(format, args...)
+}
+
+// Logf logs with the Testing it wraps, so diagnostics aren't counted as failures.
+func (r *failureRecorder) Logf(format string, args ...interface{}) {
+	r.Helper()
+	logf(r.Testing, format, args...)
+}
+
+// FailFast makes every assertion of the Comparer, such as StructCheck, Golden and ValueCheck,
+// stop the test once it has reported its failures, as the Require and Must variants do, so a
+// failed check doesn't let later assertions run against data that is already known to be bad.
+// As with those variants, t must be a Fataler, as *testing.T is, for the test to stop.
+func FailFast() Option {
+	return func(c *Comparer) {
+		c.failFast = true
+	}
+}
+
+// stopOnFailure returns the Testing an assertion should report to, and a function to defer that
+// stops the test if anything was reported to it and the Comparer was created with FailFast. It
+// first reports the invalid options and environment variables that were skipped, if any.
+func (c *Comparer) stopOnFailure(t Testing) (Testing, func()) {
+	t.Helper()
+	if !c.failFast {
+		c.reportConfigError(t)
+		return t, func() {}
+	}
+	recorder := &failureRecorder{Testing: t}
+	c.reportConfigError(recorder)
+	return recorder, func() {
+		if recorder.failed {
+			failNow(t)
+		}
+	}
+}
+
+// reportConfigError reports the invalid options and environment variables that were skipped
+// while configuring the Comparer to t.
+func (c *Comparer) reportConfigError(t Testing) {
+	t.Helper()
+	if c.configErr != nil {
+		t.Error(c.configErr)
+	}
+}
+
+// MustEqual panics with a report of the differences between json1 and json2 if there are any.
+// Like the other comparisons, it accepts documents holding any JSON value. It is meant for code
+// that runs outside tests, such as init-time sanity checks and data migration scripts, where
+// there's no Testing to report to.
+func MustEqual(json1, json2 []byte, opts ...Option) {
+	c := NewComparer(opts...)
+	if errors := c.equalValues(json1, json2); len(errors) > 0 {
+		panic(reportText(errors, c.maxErrors))
+	}
+}
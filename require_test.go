@@ -0,0 +1,146 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name           string
+		require        func(t Testing)
+		failNow        bool
+		expectedErrors []error
+	}{
+		{
+			name:    "equal map",
+			require: func(t Testing) { RequireEqualMap(t, []byte(`{"a": 1}`), []byte(`{"a": 1, "b": null}`)) },
+		},
+		{
+			name:    "map mismatch",
+			require: func(t Testing) { RequireEqualMap(t, []byte(`{"a": 1}`), []byte(`{"a": 2}`)) },
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in json2"),
+				fmt.Errorf("a mismatch. 1 vs. 2"),
+			},
+		},
+		{
+			name:    "slice mismatch",
+			require: func(t Testing) { RequireEqualSlice(t, []byte(`[1, 2]`), []byte(`[1, 3]`)) },
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in json2"),
+				fmt.Errorf("[1] mismatch. 2 vs. 3"),
+			},
+		},
+		{
+			name: "comparer options",
+			require: func(t Testing) {
+				NewComparer(WithTolerance(0.1)).RequireEqualMap(t, []byte(`{"a": 1}`), []byte(`{"a": 1.05}`))
+			},
+		},
+		{
+			name:    "struct check",
+			require: func(t Testing) { MustStructCheck(t, "testdata/complete.json", &receiveStruct{}) },
+		},
+		{
+			name:    "struct check fails",
+			require: func(t Testing) { MustStructCheck(t, "testdata/nulls.json", new(string)) },
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("invalid argument: result must be a pointer to a struct, slice, or map, but got *string"),
+			},
+		},
+		{
+			name:    "fail fast struct check",
+			require: func(t Testing) { NewComparer(FailFast()).StructCheck(t, "testdata/complete.json", &receiveStruct{}) },
+		},
+		{
+			name: "fail fast struct check fails",
+			require: func(t Testing) {
+				NewComparer(FailFast()).StructCheckBytes(t, "payload", []byte(`{"a": "x", "b": 1}`), &subStruct{})
+			},
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("error decoding json in payload: json: cannot unmarshal number into Go struct field subStruct.b of type string"),
+			},
+		},
+		{
+			name:    "fail fast value check fails",
+			require: func(t Testing) { NewComparer(FailFast()).ValueCheck(t, truncatingAmount{Cents: 1050}) },
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in jsonassert.truncatingAmount"),
+				fmt.Errorf("amount mismatch. 10.50 vs. 10.00"),
+			},
+		},
+		{
+			name:    "fail fast typed",
+			require: func(t Testing) { EqualT(t, []int{1}, []int{2}, FailFast()) },
+			failNow: true,
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf("[0] mismatch. 1 vs. 2"),
+			},
+		},
+		{
+			name:    "fail fast fluent",
+			require: func(t Testing) { For(t).Expect(`{"a": 1}`).ToEqual(`{"a": 1.0}`).WithOptions(FailFast()).Assert() },
+		},
+		{
+			name:    "without fail fast",
+			require: func(t Testing) { ValueCheck(t, truncatingAmount{Cents: 1050}) },
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in jsonassert.truncatingAmount"),
+				fmt.Errorf("amount mismatch. 10.50 vs. 10.00"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &capableTester{}
+			tt.require(fakeT)
+			if fakeT.failedNow != tt.failNow {
+				t.Errorf("FailNow called = %v, want %v", fakeT.failedNow, tt.failNow)
+			}
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestMustEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		json1    string
+		json2    string
+		opts     []Option
+		expected string
+	}{
+		{"equal", `{"a": 1, "b": ""}`, `{"a": 1}`, nil, ""},
+		{"options", `{"a": 1}`, `{"a": 1.05}`, []Option{WithTolerance(0.1)}, ""},
+		{"mismatch", `{"a": 1, "b": "x"}`, `{"a": 2, "b": "y"}`, nil, "*** 2 errors in json2\na mismatch. 1 vs. 2\nb mismatch. \"x\" vs. \"y\""},
+		{"max errors", `[1, 2, 3]`, `[4, 5, 6]`, []Option{WithMaxErrors(1)}, "*** 3 errors in json2\n[0] mismatch. 1 vs. 4\n… and 2 more errors"},
+		{"invalid", `{`, `{}`, nil, "*** 1 errors in json2\nerror unmarshalling json1: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					recovered = ""
+				}
+				if recovered != tt.expected {
+					t.Errorf("panic = %q, want %q", recovered, tt.expected)
+				}
+			}()
+			MustEqual([]byte(tt.json1), []byte(tt.json2), tt.opts...)
+		})
+	}
+}
+
+func TestRequireWithoutFailNow(t *testing.T) {
+	fakeT := &fakeTester{}
+	RequireEqualMap(fakeT, []byte(`{"a": 1}`), []byte(`{"a": 2}`))
+	checkErrors(t, []error{fmt.Errorf("*** 1 errors in json2"), fmt.Errorf("a mismatch. 1 vs. 2")}, fakeT.errors)
+}
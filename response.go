@@ -0,0 +1,69 @@
+package jsonassert
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// AssertResponse checks that resp is JSON, by its Content-Type, and compares its body with
+// expected using the options, reporting any problems to t as StructCheck does, and returns
+// whether the response is as expected. The body is read and then restored, so it can still be
+// read afterwards. Content types such as application/problem+json are accepted along with
+// application/json. It suits handler tests using httptest:
+//   rec := httptest.NewRecorder()
+//   handler.ServeHTTP(rec, req)
+//   jsonassert.AssertResponse(t, rec.Result(), expected)
+func AssertResponse(t Testing, resp *http.Response, expected []byte, opts ...Option) bool {
+	t.Helper()
+	c := NewComparer(opts...)
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	if resp == nil {
+		t.Error("response is nil")
+		return false
+	}
+	ok := true
+	contentType := resp.Header.Get("Content-Type")
+	if !isJSONContentType(contentType) {
+		t.Errorf("response Content-Type is %q, not application/json", contentType)
+		ok = false
+	}
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			t.Errorf("error reading response body: %v", err)
+			return false
+		}
+	}
+	var value1, value2 interface{}
+	var errors []error
+	if err := c.unmarshal(expected, &value1); err != nil {
+		errors = append(errors, c.invalidDocument("expected", err))
+	}
+	if err := c.unmarshal(body, &value2); err != nil {
+		errors = append(errors, c.invalidDocument("response body", err))
+	}
+	if len(errors) == 0 {
+		errors = c.compareValues("", value1, value2)
+	}
+	c.notifyErrors(t, "response", errors)
+	c.notifyDiff(t, expected, body, errors)
+	return ok && len(errors) == 0
+}
+
+// isJSONContentType returns whether contentType is application/json or a JSON-based media type,
+// such as application/problem+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,73 @@
+package jsonassert
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAssertResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expected       string
+		opts           []Option
+		expectedErrors []error
+	}{
+		{"equal", "application/json; charset=utf-8", `{"id": 1, "name": "a"}`, `{"name":"a","id":1}`, nil, nil},
+		{"problem json", "application/problem+json", `{"status": 404}`, `{"status":404}`, nil, nil},
+		{"array", "application/json", `[1, 2]`, `[1,2]`, nil, nil},
+		{"mismatch", "application/json", `{"id": 2}`, `{"id":1}`, nil, []error{
+			fmt.Errorf("*** 1 errors in response"),
+			fmt.Errorf("id mismatch. 1 vs. 2"),
+		}},
+		{"options", "application/json", `{"id": 2, "at": "now"}`, `{"id":2}`, []Option{WithIgnorePaths("at")}, nil},
+		{"content type", "text/html", `{"id": 1}`, `{"id":1}`, nil, []error{
+			fmt.Errorf(`response Content-Type is "text/html", not application/json`),
+		}},
+		{"sniffed content type", "", `{"id": 1}`, `{"id":1}`, nil, []error{
+			fmt.Errorf(`response Content-Type is "text/plain; charset=utf-8", not application/json`),
+		}},
+		{"invalid body", "application/json", `<html>`, `{"id":1}`, nil, []error{
+			fmt.Errorf("*** 1 errors in response"),
+			fmt.Errorf("error unmarshalling response body: invalid character '<' looking for beginning of value"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tt.contentType != "" {
+				rec.Header().Set("Content-Type", tt.contentType)
+			}
+			rec.WriteString(tt.body)
+			resp := rec.Result()
+			fakeT := &fakeTester{}
+			ok := AssertResponse(fakeT, resp, []byte(tt.expected), tt.opts...)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+			if ok != (len(tt.expectedErrors) == 0) {
+				t.Errorf("AssertResponse() = %v, want %v", ok, len(tt.expectedErrors) == 0)
+			}
+			if body, err := io.ReadAll(resp.Body); err != nil || string(body) != tt.body {
+				t.Errorf("restored body = %q, %v, want %q", body, err, tt.body)
+			}
+		})
+	}
+}
+
+func TestAssertResponseNil(t *testing.T) {
+	fakeT := &fakeTester{}
+	if AssertResponse(fakeT, nil, []byte(`{}`)) {
+		t.Errorf("AssertResponse(nil) = true, want false")
+	}
+	checkErrors(t, []error{fmt.Errorf("response is nil")}, fakeT.errors)
+	resp := &http.Response{Header: http.Header{"Content-Type": {"application/json"}}}
+	fakeT = &fakeTester{}
+	AssertResponse(fakeT, resp, []byte(`{}`))
+	checkErrors(t, []error{
+		fmt.Errorf("*** 1 errors in response"),
+		fmt.Errorf("error unmarshalling response body: unexpected end of JSON input"),
+	}, fakeT.errors)
+}
@@ -0,0 +1,193 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Result is the outcome of comparing two documents with Compare.
+type Result struct {
+	mismatches []Mismatch
+	stats      Stats
+	maxErrors  int
+	file       string
+}
+
+// Stats counts the values looked at by a comparison.
+type Stats struct {
+	// Values is the number of pairs of values compared. Objects and arrays are counted as well as
+	// the values in them.
+	Values int `json:"values"`
+	// Ignored is the number of values skipped by the rules that ignore them.
+	Ignored int `json:"ignored"`
+	// Mismatches is the number of differences found.
+	Mismatches int `json:"mismatches"`
+}
+
+// ReportFormat is a format for Result.Report.
+type ReportFormat string
+
+const (
+	// ReportText writes the differences one per line, as StructCheck reports them.
+	ReportText ReportFormat = "text"
+	// ReportJSON writes a JSON object with the differences and stats, for tools that read the
+	// results.
+	ReportJSON ReportFormat = "json"
+	// ReportGrouped writes the differences in groups by their kind, as GroupByKind reports them.
+	ReportGrouped ReportFormat = "grouped"
+	// ReportSARIF writes a SARIF 2.1.0 log with a result for each difference, so that fixture
+	// drift can be shown as annotations by code scanning tools.
+	ReportSARIF ReportFormat = "sarif"
+)
+
+// Compare compares json1 and json2, which may hold any JSON value, using the given options, and
+// returns the Result. It finds the same differences as EqualMap and EqualSlice, which return
+// them as errors.
+func Compare(json1, json2 []byte, opts ...Option) *Result {
+	return NewComparer(opts...).Compare(json1, json2)
+}
+
+// Compare is like the package-level Compare, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) Compare(json1, json2 []byte) *Result {
+	counting := c.With()
+	counting.stats = &Stats{}
+	errors := counting.equalValues(json1, json2)
+	result := &Result{stats: *counting.stats, maxErrors: c.maxErrors, file: c.file}
+	for _, err := range errors {
+		result.mismatches = append(result.mismatches, asMismatch(err))
+	}
+	result.stats.Mismatches = len(result.mismatches)
+	return result
+}
+
+// CompareFile is like Compare, but reads json1 from the fixture filename, which reports such as
+// ReportSARIF refer to.
+func CompareFile(filename string, json2 []byte, opts ...Option) *Result {
+	return NewComparer(opts...).CompareFile(filename, json2)
+}
+
+// CompareFile is like the package-level CompareFile, but compares the JSON using the options of
+// the Comparer.
+func (c *Comparer) CompareFile(filename string, json2 []byte) *Result {
+	text, err := c.readFile(filename)
+	if err != nil {
+		return &Result{mismatches: []Mismatch{asMismatch(err)}, stats: Stats{Mismatches: 1}, maxErrors: c.maxErrors, file: filename}
+	}
+	return c.CompareFileBytes(filename, text, json2)
+}
+
+// CompareFileBytes is like CompareFile, but compares json1, which was already read from the
+// fixture filename, so the file isn't read again.
+func CompareFileBytes(filename string, json1, json2 []byte, opts ...Option) *Result {
+	return NewComparer(opts...).CompareFileBytes(filename, json1, json2)
+}
+
+// CompareFileBytes is like the package-level CompareFileBytes, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) CompareFileBytes(filename string, json1, json2 []byte) *Result {
+	derived := c.With()
+	derived.file = filename
+	return derived.Compare(json1, json2)
+}
+
+// OK reports whether the documents are equal.
+func (r *Result) OK() bool {
+	return len(r.mismatches) == 0
+}
+
+// Errors returns the differences between the documents, in the order EqualMap returns them. A
+// document that can't be unmarshalled is reported as a Mismatch with an empty path.
+func (r *Result) Errors() []Mismatch {
+	return r.mismatches
+}
+
+// Stats returns the counts of the values looked at by the comparison.
+func (r *Result) Stats() Stats {
+	return r.stats
+}
+
+// Report writes the differences to w in the given format. The text and grouped formats write
+// nothing if the documents are equal.
+func (r *Result) Report(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportText, ReportGrouped:
+		if r.OK() {
+			return nil
+		}
+		errors := make([]error, len(r.mismatches))
+		for i, m := range r.mismatches {
+			errors[i] = m
+		}
+		text := reportText(errors, r.maxErrors)
+		if format == ReportGrouped {
+			text = strings.Join(append([]string{fmt.Sprintf("*** %d errors in json2", len(errors))}, groupErrors(errors, r.maxErrors)...), "\n")
+		}
+		_, err := io.WriteString(w, text+"\n")
+		return err
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(r.jsonReport())
+	case ReportSARIF:
+		return r.writeSARIF(w)
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// MarshalJSON encodes the result as the object written by Report with ReportJSON, without its
+// trailing newline, so a Result can be embedded in the JSON of CI tools and dashboards:
+//   {"ok":false,"mismatches":[{"path":"a","kind":"value","code":"JA003",...}],"stats":{...}}
+// Like Report, it doesn't escape <, > and &. json.Marshal escapes them again in the JSON it
+// embeds a Result in, unless it's encoded by an Encoder with SetEscapeHTML(false).
+func (r *Result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.Report(&buf, ReportJSON); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonReport is the object written by Report with ReportJSON.
+type jsonReport struct {
+	OK         bool       `json:"ok"`
+	Mismatches []Mismatch `json:"mismatches"`
+	Stats      Stats      `json:"stats"`
+}
+
+func (r *Result) jsonReport() jsonReport {
+	mismatches := r.mismatches
+	if mismatches == nil {
+		mismatches = []Mismatch{}
+	}
+	return jsonReport{r.OK(), mismatches, r.stats}
+}
+
+// reportText formats errors as notifyErrors reports them, one per line, up to maxErrors if it
+// is set.
+func reportText(errors []error, maxErrors int) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "*** %d errors in json2", len(errors))
+	for i, err := range errors {
+		if maxErrors > 0 && i == maxErrors {
+			fmt.Fprintf(&report, "\n… and %d more errors", len(errors)-i)
+			break
+		}
+		fmt.Fprintf(&report, "\n%v", err)
+	}
+	return report.String()
+}
+
+// countValue counts a pair of values looked at by Compare.
+func (c *Comparer) countValue(ignored bool) {
+	switch {
+	case c.stats == nil:
+	case ignored:
+		c.stats.Ignored++
+	default:
+		c.stats.Values++
+	}
+}
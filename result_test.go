@@ -0,0 +1,93 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		json1      string
+		json2      string
+		opts       []Option
+		mismatches []Mismatch
+		stats      Stats
+		text       string
+		grouped    string
+		json       string
+	}{
+		{
+			name:  "equal",
+			json1: `{"a": 1, "b": [true]}`,
+			json2: `{"a": 1.0, "b": [true]}`,
+			stats: Stats{Values: 4},
+			json:  `{"ok":true,"mismatches":[],"stats":{"values":4,"ignored":0,"mismatches":0}}`,
+		},
+		{
+			name:  "mismatches",
+			json1: `{"a": 1, "b": "<x>", "meta": {"id": 1}}`,
+			json2: `{"a": 2, "b": "<y>", "meta": {"id": 2}}`,
+			opts:  []Option{WithIgnorePaths("meta")},
+			mismatches: []Mismatch{
+				{Path: "a", Kind: KindValue, Code: "JA003", Expected: json.Number("1"), Actual: json.Number("2"), Message: "a mismatch. 1 vs. 2"},
+				{Path: "b", Kind: KindValue, Code: "JA003", Expected: "<x>", Actual: "<y>", Message: `b mismatch. "<x>" vs. "<y>"`},
+			},
+			stats:   Stats{Values: 3, Ignored: 1, Mismatches: 2},
+			text:    "*** 2 errors in json2\na mismatch. 1 vs. 2\nb mismatch. \"<x>\" vs. \"<y>\"\n",
+			grouped: "*** 2 errors in json2\n2 value mismatches:\n    a mismatch. 1 vs. 2\n    b mismatch. \"<x>\" vs. \"<y>\"\n",
+			json:    `{"ok":false,"mismatches":[{"path":"a","kind":"value","code":"JA003","expected":1,"actual":2,"message":"a mismatch. 1 vs. 2"},{"path":"b","kind":"value","code":"JA003","expected":"<x>","actual":"<y>","message":"b mismatch. \"<x>\" vs. \"<y>\""}],"stats":{"values":3,"ignored":1,"mismatches":2}}`,
+		},
+		{
+			name:       "invalid json",
+			json1:      `[`,
+			json2:      `[]`,
+			mismatches: []Mismatch{{Kind: KindInvalid, Code: "JA007", Message: "error unmarshalling json1: unexpected end of JSON input"}},
+			stats:      Stats{Mismatches: 1},
+			text:       "*** 1 errors in json2\nerror unmarshalling json1: unexpected end of JSON input\n",
+			grouped:    "*** 1 errors in json2\n1 invalid document:\n    error unmarshalling json1: unexpected end of JSON input\n",
+			json:       `{"ok":false,"mismatches":[{"path":"","kind":"invalid","code":"JA007","expected":null,"actual":null,"message":"error unmarshalling json1: unexpected end of JSON input"}],"stats":{"values":0,"ignored":0,"mismatches":1}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare([]byte(tt.json1), []byte(tt.json2), tt.opts...)
+			if result.OK() != (len(tt.mismatches) == 0) {
+				t.Errorf("OK() = %v", result.OK())
+			}
+			if !reflect.DeepEqual(result.Errors(), tt.mismatches) {
+				t.Errorf("Errors() = %q, want %q", result.Errors(), tt.mismatches)
+			}
+			if result.Stats() != tt.stats {
+				t.Errorf("Stats() = %+v, want %+v", result.Stats(), tt.stats)
+			}
+			for format, want := range map[ReportFormat]string{ReportText: tt.text, ReportGrouped: tt.grouped, ReportJSON: tt.json + "\n"} {
+				var sb strings.Builder
+				if err := result.Report(&sb, format); err != nil {
+					t.Fatal(err)
+				}
+				if sb.String() != want {
+					t.Errorf("Report(%s) = %q, want %q", format, sb.String(), want)
+				}
+			}
+			var report bytes.Buffer
+			if err := result.Report(&report, ReportJSON); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := result.MarshalJSON(); err != nil || string(data)+"\n" != report.String() {
+				t.Errorf("MarshalJSON() = %s, %v, want %s", data, err, report.String())
+			}
+		})
+	}
+}
+
+func TestReportUnknownFormat(t *testing.T) {
+	err := Compare([]byte(`{}`), []byte(`{}`)).Report(&strings.Builder{}, "xml")
+	if fmt.Sprint(err) != `unknown report format "xml"` {
+		t.Errorf("Report() error = %v", err)
+	}
+}
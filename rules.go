@@ -0,0 +1,203 @@
+package jsonassert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Rule is the behavior for the values at one path, as used by WithRules. The zero value
+// compares the values normally.
+type Rule struct {
+	// Ignore excludes the values from the comparison.
+	Ignore bool `json:"ignore,omitempty"`
+	// Tolerance considers two numbers equal when they differ by no more than the tolerance,
+	// in place of the tolerance set by WithTolerance.
+	Tolerance *float64 `json:"tolerance,omitempty"`
+	// Percent considers two numbers equal when they differ by no more than the percentage of
+	// the larger of their magnitudes, in place of the percentage set by WithinPercent.
+	Percent *float64 `json:"percent,omitempty"`
+	// Regex requires each value that is present to be a string matching the regular expression,
+	// in place of comparing the values with each other.
+	Regex string `json:"regex,omitempty"`
+	// IgnoreCase compares strings without regard to case.
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+	// NumericString compares strings as numbers, as described on WithNumericStrings.
+	NumericString bool `json:"numericString,omitempty"`
+	// Instant compares timestamps by the instant they denote, as described on WithTimeInstants.
+	Instant bool `json:"instant,omitempty"`
+	// Granularity compares timestamps after truncating them, as described on
+	// WithTimeGranularity. It is "date" or a duration such as "1s" or "1m".
+	Granularity string `json:"granularity,omitempty"`
+	// Epoch compares numbers with timestamps, as described on WithEpochTimes. It is the unit of
+	// the numbers, "s" or "ms".
+	Epoch string `json:"epoch,omitempty"`
+	// Within considers timestamps equal when they are no more than the duration apart, as
+	// described on TimeWithin. It is a duration such as "5s".
+	Within string `json:"within,omitempty"`
+	// Money compares objects as amounts of money, as described on WithMoney.
+	Money bool `json:"money,omitempty"`
+	// Mask hides the value in error messages and trees, as described on MaskPHI.
+	Mask bool `json:"mask,omitempty"`
+	// Scrub replaces the value with the given token before it is compared or shown, as
+	// described on Scrub.
+	Scrub string `json:"scrub,omitempty"`
+	// Scale compares numbers as exact decimals rounded to the given number of decimal places,
+	// as described on WithDecimal.
+	Scale *int `json:"scale,omitempty"`
+	// ArrayKey compares arrays of objects by pairing up their elements by the value of the key,
+	// as described on WithArrayKey.
+	ArrayKey string `json:"arrayKey,omitempty"`
+}
+
+// WithRules applies a declarative set of rules keyed by path, such as:
+//   {
+//     "claims[*].paidAmount": {"scale": 2},
+//     "meta.traceId": {"ignore": true},
+//     "orderId": {"regex": "^ord-\\d+$"}
+//   }
+// The rules are compiled once, when the option is applied, and looked up for each value during
+// the comparison. Paths use the notation described on WithIgnorePaths. When several rules match
+// the same path, a value is ignored if any of them ignores it, and otherwise the rules added
+// first take precedence. WithRules panics if a regex, granularity, epoch unit or duration can't
+// be parsed; use LoadConfig to get an error instead.
+func WithRules(rules map[string]Rule) Option {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		panic(err)
+	}
+	return withPathRules(compiled)
+}
+
+func withPathRules(rules []pathRule) Option {
+	return func(c *Comparer) {
+		c.rules = append(c.rules, rules...)
+	}
+}
+
+// pathRule is a compiled Rule, or the rule created by a path-based option like WithIgnorePaths.
+type pathRule struct {
+	pattern       pathPattern
+	ignore        bool
+	matcher       Matcher
+	tolerance     float64
+	hasTolerance  bool
+	percent       float64
+	hasPercent    bool
+	scale         int
+	hasScale      bool
+	ignoreCase    bool
+	numericString bool
+	money         bool
+	mask          bool
+	scrub         string
+	hash          *valueHash
+	arrayKey      string
+	fromPreset    bool // preset rules cover paths that may not be in every document
+	time          timeRule
+	stats         *ruleStats
+}
+
+func newPathRule(path string) pathRule {
+	return pathRule{pattern: compilePathPattern(path), stats: &ruleStats{}}
+}
+
+// compileRules compiles rules in path order, so that their precedence doesn't depend on map
+// iteration order.
+func compileRules(rules map[string]Rule) ([]pathRule, error) {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var compiled []pathRule
+	for _, path := range paths {
+		rule := rules[path]
+		pr := newPathRule(path)
+		pr.ignore, pr.ignoreCase, pr.numericString = rule.Ignore, rule.IgnoreCase, rule.NumericString
+		pr.money, pr.mask, pr.scrub, pr.time.instant = rule.Money, rule.Mask, rule.Scrub, rule.Instant
+		pr.arrayKey = rule.ArrayKey
+		if rule.Granularity != "" {
+			granularity, err := parseGranularity(rule.Granularity)
+			if err != nil {
+				return nil, fmt.Errorf("rule for %s: %v", path, err)
+			}
+			pr.time.granularity = granularity
+		}
+		if rule.Epoch != "" {
+			unit, err := parseEpochUnit(rule.Epoch)
+			if err != nil {
+				return nil, fmt.Errorf("rule for %s: %v", path, err)
+			}
+			pr.time.epochUnit = unit
+		}
+		if rule.Within != "" {
+			within, err := time.ParseDuration(rule.Within)
+			if err != nil {
+				return nil, fmt.Errorf("rule for %s: %v", path, err)
+			}
+			pr.time.within = within
+		}
+		if rule.Tolerance != nil {
+			pr.tolerance, pr.hasTolerance = *rule.Tolerance, true
+		}
+		if rule.Percent != nil {
+			pr.percent, pr.hasPercent = *rule.Percent, true
+		}
+		if rule.Scale != nil {
+			pr.scale, pr.hasScale = *rule.Scale, true
+		}
+		if rule.Regex != "" {
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("rule for %s: %v", path, err)
+			}
+			pr.matcher = regexpMatcher{re}
+		}
+		compiled = append(compiled, pr)
+	}
+	return compiled, nil
+}
+
+// ruleFor merges the rules whose path matches location.
+func (c *Comparer) ruleFor(location string) pathRule {
+	var merged pathRule
+	for _, rule := range c.rules {
+		if !rule.pattern.match(location) {
+			continue
+		}
+		rule.stats.addMatch()
+		merged.ignore = merged.ignore || rule.ignore
+		merged.ignoreCase = merged.ignoreCase || rule.ignoreCase
+		merged.numericString = merged.numericString || rule.numericString
+		merged.money = merged.money || rule.money
+		merged.time = merged.time.merge(rule.time)
+		if merged.arrayKey == "" {
+			merged.arrayKey = rule.arrayKey
+		}
+		if merged.hash == nil {
+			merged.hash = rule.hash
+		}
+		if merged.scrub == "" {
+			merged.scrub = rule.scrub
+		}
+		if merged.matcher == nil {
+			merged.matcher = rule.matcher
+		}
+		if !merged.hasTolerance && rule.hasTolerance {
+			merged.tolerance, merged.hasTolerance = rule.tolerance, true
+		}
+		if !merged.hasPercent && rule.hasPercent {
+			merged.percent, merged.hasPercent = rule.percent, true
+		}
+		if !merged.hasScale && rule.hasScale {
+			merged.scale, merged.hasScale = rule.scale, true
+		}
+	}
+	if merged.ignore && (merged.matcher != nil || merged.hasTolerance || merged.hasPercent) {
+		c.recordConflicts(location)
+	}
+	return merged
+}
@@ -0,0 +1,87 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWithRules(t *testing.T) {
+	tolerance := 0.01
+	zero := 0.0
+	percent := 1.0
+	rules := map[string]Rule{
+		"claims[*].allowed":    {Percent: &percent},
+		"claims[*].paidAmount": {Tolerance: &tolerance},
+		"claims[*].billed":     {Tolerance: &zero},
+		"items":                {ArrayKey: "id"},
+		"meta.traceId":         {Ignore: true},
+		"orderId":              {Regex: `^ord-\d+$`},
+		"state":                {IgnoreCase: true},
+	}
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"rules applied", []Option{WithRules(rules)},
+			`{"claims": [{"paidAmount": 10.001}], "meta": {"traceId": "a"}, "orderId": "ord-1"}`,
+			`{"claims": [{"paidAmount": 10.005}], "meta": {"traceId": "b"}, "orderId": "ord-2"}`, nil},
+		{"ignore case", []Option{WithRules(rules)}, `{"state": "TX", "city": "Austin"}`, `{"state": "tx", "city": "AUSTIN"}`, []error{
+			fmt.Errorf(`city mismatch. "Austin" vs. "AUSTIN"`),
+		}},
+		{"rules violated", []Option{WithRules(rules)},
+			`{"claims": [{"paidAmount": 10.001}], "total": 1.001, "orderId": "ord-1"}`,
+			`{"claims": [{"paidAmount": 10.1}], "total": 1.002, "orderId": "2"}`, []error{
+				fmt.Errorf("claims[0].paidAmount mismatch. 10.001 vs. 10.1"),
+				fmt.Errorf(`orderId mismatch. "2" doesn't match regexp "^ord-\\d+$"`),
+				fmt.Errorf("total mismatch. 1.001 vs. 1.002"),
+			}},
+		{"rule tolerance overrides global tolerance", []Option{WithTolerance(1), WithRules(rules)},
+			`{"claims": [{"billed": 1}], "total": 1}`,
+			`{"claims": [{"billed": 1.5}], "total": 1.5}`, []error{fmt.Errorf("claims[0].billed mismatch. 1 vs. 1.5")}},
+		{"rule percent", []Option{WithRules(rules)},
+			`{"claims": [{"allowed": 200}, {"allowed": 200}]}`,
+			`{"claims": [{"allowed": 202}, {"allowed": 203}]}`, []error{fmt.Errorf("claims[1].allowed mismatch. 200 vs. 203")}},
+		{"rule array key", []Option{WithRules(rules)},
+			`{"items": [{"id": 1, "a": 1}, {"id": 2, "a": 2}]}`,
+			`{"items": [{"id": 2, "a": 3}, {"id": 1, "a": 1}]}`, []error{fmt.Errorf("items[1].a mismatch. 2 vs. 3")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithRulesInvalidRegex(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || fmt.Sprint(r) != "rule for id: error parsing regexp: missing closing ): `(`" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	WithRules(map[string]Rule{"id": {Regex: "("}})
+}
+
+func TestCompileRulesErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          Rule
+		expectedError string
+	}{
+		{"regex", Rule{Regex: "("}, "rule for a: error parsing regexp: missing closing ): `(`"},
+		{"granularity", Rule{Granularity: "daily"}, `rule for a: time: invalid duration "daily"`},
+		{"epoch", Rule{Epoch: "us"}, `rule for a: epoch unit "us" isn't "s" or "ms"`},
+		{"within", Rule{Within: "soon"}, `rule for a: time: invalid duration "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileRules(map[string]Rule{"a": tt.rule})
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("expected %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
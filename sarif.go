@@ -0,0 +1,95 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// The SARIF 2.1.0 log written by ReportSARIF. Only the properties that jsonassert fills in are
+// declared.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// writeSARIF writes r as a SARIF log. Each difference is a result whose rule is the code of its
+// kind, located in the file compared by CompareFile, if any, and at its path in the document.
+func (r *Result) writeSARIF(w io.Writer) error {
+	driver := sarifDriver{Name: "jsonassert", InformationURI: "https://github.com/mypricehealth/jsonassert"}
+	for _, info := range kinds {
+		driver.Rules = append(driver.Rules, sarifRule{ID: info.code, Name: string(info.kind), ShortDescription: sarifMessage{Text: info.description}})
+	}
+	results := []sarifResult{}
+	for _, m := range r.mismatches {
+		result := sarifResult{RuleID: m.Code, Level: "error", Message: sarifMessage{Text: m.Message}}
+		var location sarifLocation
+		if r.file != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.file}}
+		}
+		if m.Path != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: m.Path}}
+		}
+		if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
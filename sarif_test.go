@@ -0,0 +1,86 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestReportSARIF(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *Result
+		results []sarifResult
+	}{
+		{
+			name:    "equal",
+			result:  CompareFile("testdata/array.json", []byte(`[{"item2": "value2"}, {"item1": "value3"}]`)),
+			results: []sarifResult{},
+		},
+		{
+			name:   "file",
+			result: CompareFile("testdata/array.json", []byte(`[{"item2": "value2"}, {"item1": "value4"}]`)),
+			results: []sarifResult{{
+				RuleID:  "JA003",
+				Level:   "error",
+				Message: sarifMessage{Text: `[1].item1 mismatch. "value3" vs. "value4"`},
+				Locations: []sarifLocation{{
+					PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "testdata/array.json"}},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "[1].item1"}},
+				}},
+			}},
+		},
+		{
+			name:   "file bytes",
+			result: CompareFileBytes("expected.json", []byte(`{"a": 1}`), []byte(`{"a": 2}`)),
+			results: []sarifResult{{
+				RuleID:  "JA003",
+				Level:   "error",
+				Message: sarifMessage{Text: "a mismatch. 1 vs. 2"},
+				Locations: []sarifLocation{{
+					PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "expected.json"}},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "a"}},
+				}},
+			}},
+		},
+		{
+			name:   "no file",
+			result: Compare([]byte(`{"a": 1}`), []byte(`{}`)),
+			results: []sarifResult{{
+				RuleID:    "JA001",
+				Level:     "error",
+				Message:   sarifMessage{Text: "a mismatch. 1 vs. <nil>"},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "a"}}}},
+			}},
+		},
+		{
+			name:   "unreadable file",
+			result: CompareFile("testdata/array.json", []byte(`[]`), WithMaxSize(8)),
+			results: []sarifResult{{
+				RuleID:    "JA007",
+				Level:     "error",
+				Message:   sarifMessage{Text: "testdata/array.json exceeds 8 bytes"},
+				Locations: []sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "testdata/array.json"}}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.result.Report(&buf, ReportSARIF); err != nil {
+				t.Fatal(err)
+			}
+			var log sarifLog
+			if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+				t.Fatal(err)
+			}
+			if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(kinds) {
+				t.Errorf("unexpected log %s", buf.String())
+			}
+			if !reflect.DeepEqual(log.Runs[0].Results, tt.results) {
+				t.Errorf("results = %+v, want %+v", log.Runs[0].Results, tt.results)
+			}
+		})
+	}
+}
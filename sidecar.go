@@ -0,0 +1,34 @@
+package jsonassert
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// sidecarExt is appended to a fixture's filename to find its sidecar rules file.
+const sidecarExt = ".rules"
+
+// sidecarOptions loads the options in the sidecar rules file next to filename. A sidecar is a
+// JSON file in the format read by LoadConfig, named after the fixture with ".rules" appended,
+// such as testdata/complete.json.rules. It returns no options if there is no sidecar.
+func sidecarOptions(filename string) ([]Option, error) {
+	path := filename + sidecarExt
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return LoadConfig(path)
+}
+
+// sidecarOptionsFS is like sidecarOptions, but looks for the sidecar in fsys.
+func sidecarOptionsFS(fsys fs.FS, filename string) ([]Option, error) {
+	path := filename + sidecarExt
+	text, err := fs.ReadFile(fsys, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(path, text)
+}
@@ -0,0 +1,34 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStructCheckSidecar(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparer       *Comparer
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"rules applied", NewComparer(), "testdata/sidecar/complete.json", &subStruct{}, nil},
+		{"options take precedence", NewComparer(WithMatcher("obj.*", MatchRegexp("^x"))), "testdata/sidecar/complete.json", &subStruct{}, []error{
+			fmt.Errorf("*** 2 errors in testdata/sidecar/complete.json"),
+			fmt.Errorf(`obj.a mismatch. "val" doesn't match regexp "^x"`),
+			fmt.Errorf(`obj.b mismatch. "val2" doesn't match regexp "^x"`),
+			fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tArr []string `json:\"arr\"`\n\tBTrue bool `json:\"b-true\"`\n\tNum float64 `json:\"num\"`\n\tObj map[string]interface{} `json:\"obj\"`\n\tStr string `json:\"str\"`"),
+		}},
+		{"bad rules", NewComparer(), "testdata/sidecar/badRules.json", &receiveStruct{}, []error{
+			fmt.Errorf(`error parsing config testdata/sidecar/badRules.json.rules: json: unknown field "ignored"`),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			tt.comparer.StructCheck(fakeT, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
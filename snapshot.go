@@ -0,0 +1,56 @@
+package jsonassert
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// snapshotDir is the directory Snapshot keeps its snapshots in, relative to the package being
+// tested.
+const snapshotDir = "testdata/snapshots"
+
+// Snapshot compares actual, which may hold any JSON value, with the snapshot kept for the test,
+// as Golden does. The snapshot is named after the test, with each subtest in a directory of its
+// parent, so the snapshot of TestClaims/denied is testdata/snapshots/TestClaims/denied.json. It
+// is created the first time the test runs, and rewritten in the update mode of Golden. Each test
+// has a single snapshot, so a test that takes several should use subtests. t must be able to
+// name the test, as *testing.T can.
+func Snapshot(t Testing, actual []byte) {
+	t.Helper()
+	NewComparer().Snapshot(t, actual)
+}
+
+// Snapshot is like the package-level Snapshot, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) Snapshot(t Testing, actual []byte) {
+	t.Helper()
+	name := testName(t)
+	if name == "" {
+		t.Errorf("Snapshot can't name the snapshot, since %T doesn't implement Name() string", t)
+		if c.failFast {
+			failNow(t)
+		}
+		return
+	}
+	c.golden(t, snapshotPath(name), actual, true)
+}
+
+// snapshotPath returns the path of the snapshot of the test with name. Characters that aren't
+// safe in filenames are replaced with "_", as are names made only of dots, so that a subtest
+// can't name a file outside snapshotDir.
+func snapshotPath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segment = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.' {
+				return r
+			}
+			return '_'
+		}, segment)
+		if strings.Trim(segment, ".") == "" {
+			segment = strings.Repeat("_", max(len(segment), 1))
+		}
+		segments[i] = segment
+	}
+	return filepath.Join(snapshotDir, filepath.Join(segments...)+".json")
+}
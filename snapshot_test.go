@@ -0,0 +1,70 @@
+package jsonassert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"TestClaims", "testdata/snapshots/TestClaims.json"},
+		{"TestClaims/denied_claim", "testdata/snapshots/TestClaims/denied_claim.json"},
+		{"TestClaims/a:b<c>#01", "testdata/snapshots/TestClaims/a_b_c__01.json"},
+		{"TestClaims/../..", "testdata/snapshots/TestClaims/__/__.json"},
+		{"TestClaims/", "testdata/snapshots/TestClaims/_.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if path := snapshotPath(tt.name); path != filepath.FromSlash(tt.expected) {
+				t.Errorf("snapshotPath(%q) = %q, want %q", tt.name, path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// the first run creates the snapshot, and later runs compare with it
+	tester := &capableTester{}
+	Snapshot(tester, []byte(`{"a":1}`))
+	checkErrors(t, nil, tester.errors)
+	path := filepath.FromSlash("testdata/snapshots/TestCapable/case.json")
+	if text, err := os.ReadFile(path); err != nil || string(text) != "{\n  \"a\": 1\n}" {
+		t.Fatalf("snapshot = %q, %v", text, err)
+	}
+	tester = &capableTester{}
+	Snapshot(tester, []byte(`{"a":1.0}`))
+	checkErrors(t, nil, tester.errors)
+	tester = &capableTester{}
+	Snapshot(tester, []byte(`{"a":2}`))
+	checkErrors(t, []error{
+		fmt.Errorf("*** 1 errors in %s", path),
+		fmt.Errorf("a mismatch. 1 vs. 2"),
+	}, tester.errors)
+
+	tester = &capableTester{}
+	NewComparer(withUpdate()).Snapshot(tester, []byte(`{"a":2}`))
+	checkErrors(t, nil, tester.errors)
+	if text, err := os.ReadFile(path); err != nil || string(text) != "{\n  \"a\": 2\n}" {
+		t.Errorf("updated snapshot = %q, %v", text, err)
+	}
+
+	fakeT := &fakeTester{}
+	Snapshot(fakeT, []byte(`{"a":1}`))
+	checkErrors(t, []error{
+		fmt.Errorf("Snapshot can't name the snapshot, since *jsonassert.fakeTester doesn't implement Name() string"),
+	}, fakeT.errors)
+}
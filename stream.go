@@ -0,0 +1,347 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EqualReaders is like EqualMap and EqualSlice, but reads the documents from r1 and r2 as it
+// compares them, rather than decoding both into memory first, for documents too large to hold
+// at once, such as multi-hundred-megabyte extracts:
+//   f1, _ := os.Open("testdata/extract.json")
+//   f2, _ := os.Open("out/extract.json")
+//   errs := jsonassert.EqualReaders(f1, f2)
+// Arrays are compared element by element and objects key by key as they are read, so memory
+// grows with the nesting of the documents rather than their size, as long as the keys of their
+// objects are in the same order. Values whose keys are in a different order are held until the
+// other document reaches the same key, and values at paths with a rule that compares them as a
+// whole, such as WithArrayKey, WithMatcher or WithMoney, are read whole. The differences are
+// the ones EqualMap finds, with two exceptions: they are reported in the order the documents
+// are read rather than with the keys of each object sorted, and the elements of arrays of
+// different lengths are compared up to the shorter length, followed by a Mismatch of
+// KindLength whose Expected and Actual are the lengths. Options that change how the text is
+// read, such as AllowNonFinite, AllowJSONC and RejectDuplicateKeys, aren't supported.
+func EqualReaders(r1, r2 io.Reader) []error {
+	return NewComparer().EqualReaders(r1, r2)
+}
+
+// EqualReaders is like the package-level EqualReaders, but compares the JSON using the options
+// of the Comparer.
+func (c *Comparer) EqualReaders(r1, r2 io.Reader) []error {
+	if c.allowNonFinite || c.allowLenientNumbers || c.allowConcatenated || c.allowJSONC || c.rejectDuplicateKeys || c.rejectInvalidUTF8 {
+		return []error{errors.New("EqualReaders doesn't support AllowNonFinite, AllowLenientNumbers, AllowConcatenated, AllowJSONC, RejectDuplicateKeys or RejectInvalidUTF8")}
+	}
+	d1, d2 := newStreamDecoder("json1", r1), newStreamDecoder("json2", r2)
+	found, err := c.compareStreams("", d1, d2, 0)
+	if err == nil {
+		err = d1.end()
+	}
+	if err == nil {
+		err = d2.end()
+	}
+	if streamErr, ok := err.(*streamError); ok {
+		return c.collect(found, []error{c.invalidDocument(streamErr.document, streamErr.err)})
+	}
+	return found
+}
+
+// streamDecoder reads the tokens of a document for EqualReaders.
+type streamDecoder struct {
+	decoder  *json.Decoder
+	document string
+}
+
+// streamError is an error reading the document of a streamDecoder.
+type streamError struct {
+	document string
+	err      error
+}
+
+func (e *streamError) Error() string {
+	return fmt.Sprintf("error unmarshalling %s: %v", e.document, e.err)
+}
+
+func newStreamDecoder(document string, r io.Reader) *streamDecoder {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	return &streamDecoder{decoder: d, document: document}
+}
+
+// token reads the next token, which must be there.
+func (d *streamDecoder) token() (json.Token, error) {
+	token, err := d.decoder.Token()
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = errors.New("unexpected end of JSON input")
+	}
+	if err != nil {
+		return nil, &streamError{d.document, err}
+	}
+	return token, nil
+}
+
+// key reads the next key of an object.
+func (d *streamDecoder) key() (string, error) {
+	token, err := d.token()
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
+}
+
+// more reports whether there is another element in the array or object being read.
+func (d *streamDecoder) more() bool {
+	return d.decoder.More()
+}
+
+// end checks that nothing follows the top-level value.
+func (d *streamDecoder) end() error {
+	if _, err := d.decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid content after top-level value")
+		}
+		return &streamError{d.document, err}
+	}
+	return nil
+}
+
+// value reads the rest of the value that starts with token into memory, as unmarshalNumbers
+// would decode it.
+func (d *streamDecoder) value(token json.Token, depth int) (interface{}, error) {
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	if depth >= maxDepth {
+		return nil, &streamError{d.document, fmt.Errorf("arrays and objects are nested more than %d levels deep", maxDepth)}
+	}
+	if delim == '[' {
+		values := []interface{}{}
+		for d.more() {
+			value, err := d.next(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err := d.token()
+		return values, err
+	}
+	values := map[string]interface{}{}
+	for d.more() {
+		key, err := d.key()
+		if err != nil {
+			return nil, err
+		}
+		if values[key], err = d.next(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	_, err := d.token()
+	return values, err
+}
+
+// next reads the next value into memory.
+func (d *streamDecoder) next(depth int) (interface{}, error) {
+	token, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+	return d.value(token, depth)
+}
+
+// skip reads the rest of the value that starts with token without keeping it.
+func (d *streamDecoder) skip(token json.Token) error {
+	if _, ok := token.(json.Delim); !ok {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		token, err := d.token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// compareStreams compares the next values of d1 and d2, at location, reading arrays and objects
+// in both a piece at a time unless the rule for location compares them as a whole.
+func (c *Comparer) compareStreams(location string, d1, d2 *streamDecoder, depth int) ([]error, error) {
+	token1, err := d1.token()
+	if err != nil {
+		return nil, err
+	}
+	token2, err := d2.token()
+	if err != nil {
+		return nil, err
+	}
+	rule := c.ruleFor(location)
+	if rule.ignore {
+		c.countValue(true)
+		c.traceIgnored(location)
+		if err := d1.skip(token1); err != nil {
+			return nil, err
+		}
+		return nil, d2.skip(token2)
+	}
+	if depth >= maxDepth {
+		return nil, &streamError{d1.document, fmt.Errorf("arrays and objects are nested more than %d levels deep", maxDepth)}
+	}
+	wholeValue := rule.scrub != "" || rule.hash != nil || rule.matcher != nil || rule.money || rule.arrayKey != ""
+	if token1 == token2 && !wholeValue {
+		switch token1 {
+		case json.Delim('['):
+			c.countValue(false)
+			return c.compareArrayStreams(location, d1, d2, depth)
+		case json.Delim('{'):
+			c.countValue(false)
+			return c.compareObjectStreams(location, d1, d2, depth)
+		}
+	}
+	value1, err := d1.value(token1, depth)
+	if err != nil {
+		return nil, err
+	}
+	value2, err := d2.value(token2, depth)
+	if err != nil {
+		return nil, err
+	}
+	return c.compareValuesWithRule(location, value1, value2, rule), nil
+}
+
+// compareArrayStreams compares the elements of the arrays being read by d1 and d2 in pairs,
+// and then their lengths.
+func (c *Comparer) compareArrayStreams(location string, d1, d2 *streamDecoder, depth int) ([]error, error) {
+	var errors []error
+	n := 0
+	for ; d1.more() && d2.more(); n++ {
+		more, err := c.compareStreams(fmt.Sprintf("%s[%d]", location, n), d1, d2, depth+1)
+		errors = c.collect(errors, more)
+		if err != nil {
+			return errors, err
+		}
+	}
+	len1, err := d1.skipElements(n)
+	if err != nil {
+		return errors, err
+	}
+	len2, err := d2.skipElements(n)
+	if err != nil {
+		return errors, err
+	}
+	if len1 != len2 {
+		m := c.mismatch(KindLength, location, len1, len2, plural(len1, "element"), plural(len2, "element"))
+		errors = c.collect(errors, []error{m})
+	}
+	if c.trace != nil && len1 == 0 && len2 == 0 {
+		c.traceValue(location, []interface{}{}, []interface{}{}, pathRule{}, "", nil)
+	}
+	return errors, nil
+}
+
+// skipElements reads the rest of the array being read, after the first n elements, and returns
+// its length.
+func (d *streamDecoder) skipElements(n int) (int, error) {
+	for ; d.more(); n++ {
+		token, err := d.token()
+		if err != nil {
+			return 0, err
+		}
+		if err := d.skip(token); err != nil {
+			return 0, err
+		}
+	}
+	_, err := d.token()
+	return n, err
+}
+
+// compareObjectStreams compares the values of the objects being read by d1 and d2 key by key.
+// While the keys are in the same order, the values are compared as they are read. The value of
+// a key that the other object hasn't reached yet is held until it does, or until the end of the
+// objects, when it is compared with a missing value.
+func (c *Comparer) compareObjectStreams(location string, d1, d2 *streamDecoder, depth int) ([]error, error) {
+	var errors []error
+	held1, held2 := map[string]interface{}{}, map[string]interface{}{}
+	empty := true
+	for more1, more2 := d1.more(), d2.more(); more1 || more2; more1, more2 = d1.more(), d2.more() {
+		empty = false
+		var key1, key2 string
+		var err error
+		if more1 {
+			if key1, err = d1.key(); err != nil {
+				return errors, err
+			}
+		}
+		if more2 {
+			if key2, err = d2.key(); err != nil {
+				return errors, err
+			}
+		}
+		if more1 && more2 && key1 == key2 {
+			more, err := c.compareStreams(getLocation(location, key1), d1, d2, depth+1)
+			errors = c.collect(errors, more)
+			if err != nil {
+				return errors, err
+			}
+			continue
+		}
+		if more1 {
+			if errors, err = c.holdStreamValue(errors, location, key1, d1, held1, held2, depth, false); err != nil {
+				return errors, err
+			}
+		}
+		if more2 {
+			if errors, err = c.holdStreamValue(errors, location, key2, d2, held2, held1, depth, true); err != nil {
+				return errors, err
+			}
+		}
+	}
+	for _, d := range []*streamDecoder{d1, d2} {
+		if _, err := d.token(); err != nil {
+			return errors, err
+		}
+	}
+	// the values whose keys only one of the objects has
+	for _, key := range keys(held1) {
+		errors = c.collect(errors, c.compareKey(location, key, held1, held2))
+	}
+	if c.allowExtraKeys {
+		c.recordExtraKeys(location, held1, held2)
+	} else {
+		for _, key := range keys(held2) {
+			errors = c.collect(errors, c.compareKey(location, key, held1, held2))
+		}
+	}
+	if c.trace != nil && empty {
+		empty := map[string]interface{}{}
+		c.traceValue(location, empty, empty, pathRule{}, "", nil)
+	}
+	return errors, nil
+}
+
+// holdStreamValue reads the value of key from d, which is the second document if second is set.
+// If the other document's value of key is in otherHeld, it compares the values, and otherwise it
+// holds the value in held.
+func (c *Comparer) holdStreamValue(errors []error, location, key string, d *streamDecoder, held, otherHeld map[string]interface{}, depth int, second bool) ([]error, error) {
+	value, err := d.next(depth + 1)
+	if err != nil {
+		return errors, err
+	}
+	other, ok := otherHeld[key]
+	if !ok {
+		held[key] = value
+		return errors, nil
+	}
+	delete(otherHeld, key)
+	if second {
+		value, other = other, value
+	}
+	return c.collect(errors, c.compareValues(getLocation(location, key), value, other)), nil
+}
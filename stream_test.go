@@ -0,0 +1,113 @@
+package jsonassert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEqualReaders(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparer       *Comparer
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"equal", NewComparer(), `{"a": 1, "b": [1, {"c": "x"}], "d": {}}`, `{"a":1.0,"b":[1,{"c":"x"}],"d":{}}`, nil},
+		{"lenient", NewComparer(), `{"a": "", "b": [], "c": {"d": 0}}`, `{"a":null,"b":null}`, nil},
+		{"values in order", NewComparer(), `{"z": 1, "a": {"b": 2}}`, `{"z":2,"a":{"b":3}}`, []error{
+			fmt.Errorf("z mismatch. 1 vs. 2"),
+			fmt.Errorf("a.b mismatch. 2 vs. 3"),
+		}},
+		{"keys out of order", NewComparer(), `{"a": 1, "b": {"c": 2}, "d": 3}`, `{"d":4,"b":{"c":2},"a":1}`, []error{
+			fmt.Errorf("d mismatch. 3 vs. 4"),
+		}},
+		{"missing and extra keys", NewComparer(), `{"a": 1, "b": 2}`, `{"a":1,"c":3}`, []error{
+			fmt.Errorf("b mismatch. 2 vs. <nil>"),
+			fmt.Errorf("c mismatch. <nil> vs. 3"),
+		}},
+		{"allow extra keys", NewComparer(AllowExtraKeys()), `{"a": 1}`, `{"c":3,"a":1}`, nil},
+		{"strict presence", NewComparer(StrictPresence()), `{"a": null}`, `{}`, []error{
+			fmt.Errorf("a mismatch. <nil> vs. <absent>"),
+		}},
+		{"lengths", NewComparer(), `{"a": [1, 2, 3]}`, `{"a":[1,5]}`, []error{
+			fmt.Errorf("a[1] mismatch. 2 vs. 5"),
+			fmt.Errorf("a mismatch. 3 elements vs. 2 elements"),
+		}},
+		{"types", NewComparer(), `{"a": [1], "b": {"c": 1}}`, `{"a":{"c":1},"b":"x"}`, []error{
+			fmt.Errorf("a mismatch. [1] vs. map[c:1]"),
+			fmt.Errorf("b mismatch. map[c:1] vs. \"x\""),
+		}},
+		{"ignored", NewComparer(WithIgnorePaths("a", "b[*].id")), `{"a": [1, {"x": 2}], "b": [{"id": 1, "v": 1}]}`, `{"a":3,"b":[{"id":2,"v":1}]}`, nil},
+		{"array key", NewComparer(WithArrayKey("items", "id")), `{"items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`, `{"items":[{"id":2,"v":2},{"id":1,"v":3}]}`, []error{
+			fmt.Errorf("items[0].v mismatch. 1 vs. 3"),
+		}},
+		{"tolerance", NewComparer(WithTolerance(0.1)), `[1.0, 2.0]`, `[1.05,2.5]`, []error{
+			fmt.Errorf("[1] mismatch. 2.0 vs. 2.5"),
+		}},
+		{"large integers", NewComparer(), `[9007199254740993, 1e0]`, `[9007199254740992,1.0]`, []error{
+			fmt.Errorf("[0] mismatch. 9007199254740993 vs. 9007199254740992"),
+		}},
+		{"scalars", NewComparer(), `"a"`, `"b"`, []error{
+			fmt.Errorf(` mismatch. "a" vs. "b"`),
+		}},
+		{"invalid json1", NewComparer(), `{"a": 1,}`, `{"a":1}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character ',' looking for beginning of value"),
+		}},
+		{"truncated json2", NewComparer(), `{"a": 1, "b": 2}`, `{"a":2,"b":`, []error{
+			fmt.Errorf("a mismatch. 1 vs. 2"),
+			fmt.Errorf("error unmarshalling json2: unexpected end of JSON input"),
+		}},
+		{"empty", NewComparer(), ``, `{}`, []error{
+			fmt.Errorf("error unmarshalling json1: unexpected end of JSON input"),
+		}},
+		{"trailing content", NewComparer(), `{"a": 1}`, `{"a":1}{"a":2}`, []error{
+			fmt.Errorf("error unmarshalling json2: invalid content after top-level value"),
+		}},
+		{"unsupported", NewComparer(AllowJSONC()), `{}`, `{}`, []error{
+			fmt.Errorf("EqualReaders doesn't support AllowNonFinite, AllowLenientNumbers, AllowConcatenated, AllowJSONC, RejectDuplicateKeys or RejectInvalidUTF8"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := tt.comparer.EqualReaders(strings.NewReader(tt.json1), strings.NewReader(tt.json2))
+			checkErrors(t, tt.expectedErrors, errors)
+		})
+	}
+}
+
+// TestEqualReadersMatchesEqualValues checks that EqualReaders finds the same differences as the
+// comparisons that decode the documents, for arrays of the same lengths.
+func TestEqualReadersMatchesEqualValues(t *testing.T) {
+	documents := []string{
+		`{"a": 1, "b": "x", "c": [1, 2], "d": {"e": null, "f": true}}`,
+		`{"d": {"f": false}, "c": [1, 3], "b": "", "a": 1.0}`,
+		`{"a": 2, "c": null, "d": {"e": {}, "f": true, "g": [{}]}}`,
+		`{"b": "x", "a": 1, "d": {"e": [], "f": true}, "c": [1, 2]}`,
+		`{"d": "x"}`,
+		`{}`,
+	}
+	comparers := []*Comparer{NewComparer(), NewComparer(Strict()), NewComparer(StrictPresence()), NewComparer(AllowExtraKeys()), NewComparer(WithIgnorePaths("d.f"))}
+	for _, c := range comparers {
+		for _, json1 := range documents {
+			for _, json2 := range documents {
+				want := errorMessages(c.equalValues([]byte(json1), []byte(json2)))
+				got := errorMessages(c.EqualReaders(strings.NewReader(json1), strings.NewReader(json2)))
+				if strings.Join(got, "\n") != strings.Join(want, "\n") {
+					t.Errorf("EqualReaders(%s, %s) = %q, want %q", json1, json2, got, want)
+				}
+			}
+		}
+	}
+}
+
+func errorMessages(errors []error) []string {
+	messages := []string{}
+	for _, err := range errors {
+		messages = append(messages, err.Error())
+	}
+	sort.Strings(messages)
+	return messages
+}
@@ -0,0 +1,119 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SummaryOnly reports the mismatches found by StructCheck and the other assertions as a single
+// line, such as "testdata/complete.json: 37 mismatches across 5 top-level keys; set
+// JSONASSERT_VERBOSE=1 for details", to keep CI logs readable. With JSONASSERT_VERBOSE set, the
+// mismatches are listed as usual.
+func SummaryOnly() Option {
+	return func(c *Comparer) {
+		c.summaryOnly = true
+	}
+}
+
+// GroupByKind reports the mismatches found by StructCheck and the other assertions in groups by
+// their kind, such as missing values, extra values and type mismatches, with a line counting the
+// mismatches of each group and their messages indented beneath it:
+//   *** 5 errors in testdata/claim.json
+//   2 missing values:
+//       claims[0].memberId mismatch. "M1" vs. <nil>
+//       claims[1].memberId mismatch. "M2" vs. <nil>
+//   3 type mismatches:
+//       ...
+// For large contract drifts, the groups tell the overall story that a flat list obscures. The
+// groups are in the order of the codes of their kinds, and WithMaxErrors limits the messages
+// listed in each group. SummaryOnly takes precedence.
+func GroupByKind() Option {
+	return func(c *Comparer) {
+		c.groupByKind = true
+	}
+}
+
+// groupErrors formats errors in groups by their kind, as GroupByKind reports them, listing up to
+// maxErrors messages in each group if it is set.
+func groupErrors(errors []error, maxErrors int) []string {
+	byKind := map[MismatchKind][]error{}
+	for _, err := range errors {
+		kind := asMismatch(err).Kind
+		byKind[kind] = append(byKind[kind], err)
+	}
+	var groups []string
+	for _, info := range kinds {
+		group := byKind[info.kind]
+		if len(group) == 0 {
+			continue
+		}
+		var text strings.Builder
+		fmt.Fprintf(&text, "%s:", plural(len(group), info.noun))
+		for i, err := range group {
+			if maxErrors > 0 && i == maxErrors {
+				fmt.Fprintf(&text, "\n    … and %d more", len(group)-i)
+				break
+			}
+			fmt.Fprintf(&text, "\n    %s", strings.ReplaceAll(err.Error(), "\n", "\n    "))
+		}
+		groups = append(groups, text.String())
+	}
+	return groups
+}
+
+// WithMaxErrors limits the mismatches that StructCheck and the other assertions report to the
+// first n, followed by a line such as "… and 9950 more errors", so a document that is wrong
+// throughout doesn't flood the test log. The "*** N errors" line before them still counts them
+// all. Text reports written by Result.Report are limited the same way, but the errors returned
+// by EqualMap and EqualSlice are not. 0 reports every mismatch. JSONASSERT_MAX_ERRORS overrides
+// n.
+func WithMaxErrors(n int) Option {
+	return func(c *Comparer) {
+		c.maxErrors = n
+	}
+}
+
+// summarize writes the single line reported for errors by SummaryOnly. The paths of the errors
+// are written in format.
+func summarize(filename string, errors []error, format PathFormat) string {
+	seen := map[string]bool{}
+	noun := "key"
+	for _, err := range errors {
+		path := asMismatch(err).Path
+		if path == "" {
+			continue
+		}
+		top, index := topLevel(path, format)
+		if index {
+			noun = "element"
+		}
+		seen[top] = true
+	}
+	summary := fmt.Sprintf("%s: %s", filename, plural(len(errors), "mismatch"))
+	if len(seen) > 0 {
+		summary += fmt.Sprintf(" across %s", plural(len(seen), "top-level "+noun))
+	}
+	return summary + fmt.Sprintf("; set %s=1 for details", EnvVerbose)
+}
+
+// topLevel returns the first key or index of path, which is written in format, such as "claims"
+// for "claims[0].amount" or "[2]" for "[2].id", and whether it is an index.
+func topLevel(path string, format PathFormat) (string, bool) {
+	segments := splitPath(path, format)
+	if len(segments) == 0 {
+		return path, false
+	}
+	return formatSegments(segments[:1], format), segments[0].index
+}
+
+// plural writes n and noun, adding "s" or "es" to the noun unless n is 1.
+func plural(n int, noun string) string {
+	switch {
+	case n == 1:
+	case strings.HasSuffix(noun, "ch"):
+		noun += "es"
+	default:
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", n, noun)
+}
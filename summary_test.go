@@ -0,0 +1,125 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSummaryOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"keys", []Option{SummaryOnly()}, `{"a": {"b": 1, "c": 2}, "d": [1, 2], "e": "x"}`, `{"a": {"b": 2, "c": 3}, "d": [1, 3], "e": "x"}`, []error{
+			fmt.Errorf("actual: 3 mismatches across 2 top-level keys; set JSONASSERT_VERBOSE=1 for details"),
+		}},
+		{"one", []Option{SummaryOnly()}, `{"a": 1}`, `{"a": 2}`, []error{
+			fmt.Errorf("actual: 1 mismatch across 1 top-level key; set JSONASSERT_VERBOSE=1 for details"),
+		}},
+		{"elements", []Option{SummaryOnly()}, `[{"a": 1}, {"a": 1}]`, `[{"a": 2}, {"a": 2}]`, []error{
+			fmt.Errorf("actual: 2 mismatches across 2 top-level elements; set JSONASSERT_VERBOSE=1 for details"),
+		}},
+		{"invalid", []Option{SummaryOnly()}, `{`, `{}`, []error{
+			fmt.Errorf("actual: 1 mismatch; set JSONASSERT_VERBOSE=1 for details"),
+		}},
+		{"verbose", []Option{SummaryOnly(), withVerbose()}, `{"a": 1}`, `{"a": 2}`, []error{
+			fmt.Errorf("*** 1 errors in actual"),
+			fmt.Errorf("a mismatch. 1 vs. 2"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			For(fakeT).Expect(tt.json2).ToEqual(tt.json1).WithOptions(tt.opts...).Assert()
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestStructCheckSummaryOnly(t *testing.T) {
+	fakeT := &fakeTester{}
+	NewComparer(SummaryOnly()).StructCheck(fakeT, "testdata/complete.json", &subStruct{})
+	checkErrors(t, []error{
+		fmt.Errorf("testdata/complete.json: 5 mismatches across 5 top-level keys; set JSONASSERT_VERBOSE=1 for details"),
+		fmt.Errorf("suggested field additions for jsonassert.subStruct:\n\tArr []string `json:\"arr\"`\n\tBTrue bool `json:\"b-true\"`\n\tNum float64 `json:\"num\"`\n\tObj map[string]interface{} `json:\"obj\"`\n\tStr string `json:\"str\"`"),
+	}, fakeT.errors)
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	json1, json2 := `[1, 2, 3, 4, 5]`, `[6, 7, 8, 9, 10]`
+	tests := []struct {
+		name           string
+		opts           []Option
+		expectedErrors []error
+	}{
+		{"limited", []Option{WithMaxErrors(2)}, []error{
+			fmt.Errorf("*** 5 errors in actual"),
+			fmt.Errorf("[0] mismatch. 1 vs. 6"),
+			fmt.Errorf("[1] mismatch. 2 vs. 7"),
+			fmt.Errorf("… and 3 more errors"),
+		}},
+		{"not reached", []Option{WithMaxErrors(5)}, []error{
+			fmt.Errorf("*** 5 errors in actual"),
+			fmt.Errorf("[0] mismatch. 1 vs. 6"),
+			fmt.Errorf("[1] mismatch. 2 vs. 7"),
+			fmt.Errorf("[2] mismatch. 3 vs. 8"),
+			fmt.Errorf("[3] mismatch. 4 vs. 9"),
+			fmt.Errorf("[4] mismatch. 5 vs. 10"),
+		}},
+		{"unlimited", []Option{WithMaxErrors(2), WithMaxErrors(0)}, []error{
+			fmt.Errorf("*** 5 errors in actual"),
+			fmt.Errorf("[0] mismatch. 1 vs. 6"),
+			fmt.Errorf("[1] mismatch. 2 vs. 7"),
+			fmt.Errorf("[2] mismatch. 3 vs. 8"),
+			fmt.Errorf("[3] mismatch. 4 vs. 9"),
+			fmt.Errorf("[4] mismatch. 5 vs. 10"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			For(fakeT).Expect(json2).ToEqual(json1).WithOptions(tt.opts...).Assert()
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestGroupByKind(t *testing.T) {
+	json1 := `{"a": 1, "b": "x", "c": [1, 2], "d": {"e": true}, "f": null}`
+	json2 := `{"a": 2, "b": 1, "c": [1], "f": "y", "g": 3}`
+	tests := []struct {
+		name           string
+		opts           []Option
+		expectedErrors []error
+	}{
+		{"grouped", []Option{GroupByKind()}, []error{
+			fmt.Errorf("*** 6 errors in actual"),
+			fmt.Errorf("1 missing value:\n    d.e mismatch. true vs. <nil>"),
+			fmt.Errorf("1 type mismatch:\n    b mismatch. \"x\" vs. 1"),
+			fmt.Errorf("1 value mismatch:\n    a mismatch. 1 vs. 2"),
+			fmt.Errorf("2 extra values:\n    f mismatch. <nil> vs. \"y\"\n    g mismatch. <nil> vs. 3"),
+			fmt.Errorf("1 length mismatch:\n    c mismatch. [1 2] vs. [1]"),
+		}},
+		{"max errors", []Option{GroupByKind(), WithMaxErrors(1)}, []error{
+			fmt.Errorf("*** 6 errors in actual"),
+			fmt.Errorf("1 missing value:\n    d.e mismatch. true vs. <nil>"),
+			fmt.Errorf("1 type mismatch:\n    b mismatch. \"x\" vs. 1"),
+			fmt.Errorf("1 value mismatch:\n    a mismatch. 1 vs. 2"),
+			fmt.Errorf("2 extra values:\n    f mismatch. <nil> vs. \"y\"\n    … and 1 more"),
+			fmt.Errorf("1 length mismatch:\n    c mismatch. [1 2] vs. [1]"),
+		}},
+		{"summary only", []Option{GroupByKind(), SummaryOnly()}, []error{
+			fmt.Errorf("actual: 6 mismatches across 6 top-level keys; set JSONASSERT_VERBOSE=1 for details"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			For(fakeT).Expect(json2).ToEqual(json1).WithOptions(tt.opts...).Assert()
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
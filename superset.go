@@ -0,0 +1,43 @@
+package jsonassert
+
+// Superset checks that json2 includes everything in json1, as AllowExtraKeys does, and also
+// returns the paths of the keys that json2 adds, in the order they are found, so a test can
+// track an API adding to its responses without failing on it:
+//   extra, errs := jsonassert.Superset(expected, body)
+//   for _, err := range errs {
+//     t.Error(err)
+//   }
+//   if len(extra) > 0 {
+//     t.Logf("new keys: %v", extra)
+//   }
+// A key is reported at the outermost path it was added, so the keys nested in an added object
+// aren't reported again. Keys at ignored paths aren't reported. The documents may hold any JSON
+// value.
+func Superset(json1, json2 []byte, opts ...Option) ([]string, []error) {
+	return NewComparer(opts...).Superset(json1, json2)
+}
+
+// Superset is like the package-level Superset, but compares the JSON using the options of the
+// Comparer.
+func (c *Comparer) Superset(json1, json2 []byte) ([]string, []error) {
+	recording := c.With(AllowExtraKeys())
+	recording.extraKeys = &[]string{}
+	errors := recording.equalValues(json1, json2)
+	return *recording.extraKeys, errors
+}
+
+// recordExtraKeys records the keys in map2 that aren't in map1, for Superset.
+func (c *Comparer) recordExtraKeys(location string, map1, map2 map[string]interface{}) {
+	if c.extraKeys == nil {
+		return
+	}
+	for _, key := range keys(map2) {
+		if _, ok := map1[key]; ok {
+			continue
+		}
+		keyLocation := getLocation(location, key)
+		if !c.ruleFor(keyLocation).ignore {
+			*c.extraKeys = append(*c.extraKeys, keyLocation)
+		}
+	}
+}
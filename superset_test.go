@@ -0,0 +1,39 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSuperset(t *testing.T) {
+	tests := []struct {
+		name              string
+		opts              []Option
+		json1             string
+		json2             string
+		expectedExtraKeys []string
+		expectedErrors    []error
+	}{
+		{"same", nil, `{"a": 1}`, `{"a": 1}`, []string{}, nil},
+		{"extra keys", nil, `{"a": 1, "b": {"c": 1}, "d": null}`, `{"a": 1, "b": {"c": 1, "e": 2}, "f": {"g": 1}, "d": {"h": 1}}`, []string{"b.e", "d.h", "f"}, nil},
+		{"in arrays", nil, `[{"a": 1}, {"a": 2}]`, `[{"a": 1, "b": 1}, {"a": 2}]`, []string{"[0].b"}, nil},
+		{"missing and changed", nil, `{"a": 1, "b": 2}`, `{"a": 2, "c": 3}`, []string{"c"}, []error{
+			fmt.Errorf("a mismatch. 1 vs. 2"),
+			fmt.Errorf("b mismatch. 2 vs. <nil>"),
+		}},
+		{"ignored", []Option{WithIgnorePaths("meta")}, `{"a": 1}`, `{"a": 1, "meta": {}, "b": 1}`, []string{"b"}, nil},
+		{"invalid", nil, `{"a": 1}`, `{`, []string{}, []error{
+			fmt.Errorf("error unmarshalling json2: unexpected end of JSON input"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extraKeys, errs := NewComparer(tt.opts...).Superset([]byte(tt.json1), []byte(tt.json2))
+			if !reflect.DeepEqual(extraKeys, tt.expectedExtraKeys) {
+				t.Errorf("extra keys mismatch. want: %v, got: %v", tt.expectedExtraKeys, extraKeys)
+			}
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
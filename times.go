@@ -0,0 +1,195 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// timestampLayouts are the layouts of the strings compared as timestamps. Timestamps without a
+// zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// timeRule is how the timestamps at a path are compared.
+type timeRule struct {
+	instant     bool
+	granularity time.Duration
+	epochUnit   time.Duration
+	within      time.Duration
+}
+
+func (r timeRule) enabled() bool {
+	return r.instant || r.granularity > 0 || r.epochUnit > 0 || r.within > 0
+}
+
+// merge fills the settings that are unset in r from other.
+func (r timeRule) merge(other timeRule) timeRule {
+	r.instant = r.instant || other.instant
+	if r.granularity == 0 {
+		r.granularity = other.granularity
+	}
+	if r.epochUnit == 0 {
+		r.epochUnit = other.epochUnit
+	}
+	if r.within == 0 {
+		r.within = other.within
+	}
+	return r
+}
+
+// WithTimeInstants compares strings that are both RFC 3339 timestamps by the instant they
+// denote, so "2024-06-01T00:00:00Z" and "2024-05-31T19:00:00-05:00" are equal. Other strings are
+// compared as usual. Use Rule.Instant to compare only some paths this way.
+func WithTimeInstants() Option {
+	return func(c *Comparer) {
+		c.time.instant = true
+	}
+}
+
+// DateGranularity is the granularity that compares only the calendar dates of timestamps.
+const DateGranularity = 24 * time.Hour
+
+// WithTimeGranularity compares timestamps, like WithTimeInstants, after truncating them to
+// granularity, such as time.Second or time.Minute. A granularity of DateGranularity or more
+// compares the calendar dates as written, ignoring the time and zone, so "2024-06-01" and
+// "2024-06-01T18:30:00-05:00" are equal. Use Rule.Granularity to compare only some paths this
+// way.
+func WithTimeGranularity(granularity time.Duration) Option {
+	return func(c *Comparer) {
+		c.time.granularity = granularity
+	}
+}
+
+// parseGranularity parses the granularity of a Rule, which is "date" or a duration such as
+// "1m".
+func parseGranularity(s string) (time.Duration, error) {
+	if s == "date" {
+		return DateGranularity, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("granularity %s isn't positive", s)
+	}
+	return d, nil
+}
+
+// WithEpochTimes compares numbers with timestamps, like WithTimeInstants, by reading the numbers
+// as the time since the Unix epoch in unit, such as time.Second or time.Millisecond. With
+// time.Second, 1717200000 and "2024-06-01T00:00:00Z" are equal. Use Rule.Epoch to compare only
+// some paths this way.
+func WithEpochTimes(unit time.Duration) Option {
+	return func(c *Comparer) {
+		c.time.epochUnit = unit
+	}
+}
+
+// parseEpochUnit parses the epoch unit of a Rule, which is "s" or "ms".
+func parseEpochUnit(s string) (time.Duration, error) {
+	switch s {
+	case "s":
+		return time.Second, nil
+	case "ms":
+		return time.Millisecond, nil
+	}
+	return 0, fmt.Errorf(`epoch unit %q isn't "s" or "ms"`, s)
+}
+
+// TimeWithin compares timestamps, like WithTimeInstants, and considers them equal when they are
+// no more than d apart, for values like createdAt in tests against live systems. Use
+// Rule.Within to compare only some paths this way, or MatchNow to check a timestamp against the
+// time of the test.
+func TimeWithin(d time.Duration) Option {
+	return func(c *Comparer) {
+		c.time.within = d
+	}
+}
+
+// MatchNow returns a Matcher that accepts timestamps no more than d before or after the time
+// they are matched.
+func MatchNow(d time.Duration) Matcher {
+	return nowMatcher{d}
+}
+
+type nowMatcher struct {
+	within time.Duration
+}
+
+func (m nowMatcher) Match(value interface{}) bool {
+	t, ok := parseTimestamp(value, timeRule{})
+	return ok && absDuration(time.Since(t)) <= m.within
+}
+
+func (m nowMatcher) String() string {
+	return fmt.Sprintf("a timestamp within %v of now", m.within)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// equalTimes reports whether t1 and t2 are equal under rule.
+func equalTimes(t1, t2 time.Time, rule timeRule) bool {
+	switch {
+	case rule.within > 0:
+		return absDuration(t1.Sub(t2)) <= rule.within
+	case rule.granularity >= DateGranularity:
+		y1, m1, d1 := t1.Date()
+		y2, m2, d2 := t2.Date()
+		return y1 == y2 && m1 == m2 && d1 == d2
+	case rule.granularity > 0:
+		return t1.Truncate(rule.granularity).Equal(t2.Truncate(rule.granularity))
+	}
+	return t1.Equal(t2)
+}
+
+// parseTimestamp parses the strings compared as timestamps, and numbers if rule has an epoch
+// unit.
+func parseTimestamp(value interface{}, rule timeRule) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	case json.Number:
+		if rule.epochUnit == 0 {
+			break
+		}
+		r, ok := exactNumber(v)
+		if !ok {
+			break
+		}
+		ns := new(big.Rat).Mul(r, new(big.Rat).SetInt64(int64(rule.epochUnit)))
+		whole := new(big.Int).Quo(ns.Num(), ns.Denom())
+		if whole.IsInt64() {
+			return time.Unix(0, whole.Int64()).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compareTimes compares value1 and value2 as timestamps if both are timestamps. The second
+// result is false if they aren't, so they should be compared as usual.
+func (c *Comparer) compareTimes(location string, value1, value2 interface{}, rule timeRule) ([]error, bool) {
+	t1, ok1 := parseTimestamp(value1, rule)
+	t2, ok2 := parseTimestamp(value2, rule)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	if !equalTimes(t1, t2, rule) {
+		return []error{c.notifyMismatch(KindValue, location, value1, value2)}, true
+	}
+	return nil, true
+}
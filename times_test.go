@@ -0,0 +1,106 @@
+package jsonassert
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCompareTimes(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"strings by default", nil, `{"a": "2024-06-01T00:00:00Z"}`, `{"a": "2024-05-31T19:00:00-05:00"}`, []error{
+			fmt.Errorf(`a mismatch. "2024-06-01T00:00:00Z" vs. "2024-05-31T19:00:00-05:00"`),
+		}},
+		{"same instant", []Option{WithTimeInstants()}, `{"a": "2024-06-01T00:00:00Z", "b": "2024-06-01T12:00:00.5+02:00"}`, `{"a": "2024-05-31T19:00:00-05:00", "b": "2024-06-01T10:00:00.500Z"}`, nil},
+		{"different instant", []Option{WithTimeInstants()}, `{"a": "2024-06-01T00:00:00Z"}`, `{"a": "2024-06-01T00:00:00-05:00"}`, []error{
+			fmt.Errorf(`a mismatch. "2024-06-01T00:00:00Z" vs. "2024-06-01T00:00:00-05:00"`),
+		}},
+		{"not timestamps", []Option{WithTimeInstants()}, `{"a": "2024-06-01T00:00:00Z", "b": "x"}`, `{"a": "soon", "b": "x"}`, []error{
+			fmt.Errorf(`a mismatch. "2024-06-01T00:00:00Z" vs. "soon"`),
+		}},
+		{"rule", []Option{WithRules(map[string]Rule{"created": {Instant: true}})}, `{"created": "2024-06-01T00:00:00Z", "updated": "2024-06-01T00:00:00Z"}`, `{"created": "2024-06-01T02:00:00+02:00", "updated": "2024-06-01T02:00:00+02:00"}`, []error{
+			fmt.Errorf(`updated mismatch. "2024-06-01T00:00:00Z" vs. "2024-06-01T02:00:00+02:00"`),
+		}},
+		{"seconds", []Option{WithTimeGranularity(time.Second)}, `{"a": "2024-06-01T00:00:00.123Z", "b": "2024-06-01T00:00:00Z"}`, `{"a": "2024-06-01T00:00:00.987Z", "b": "2024-06-01T00:00:01Z"}`, []error{
+			fmt.Errorf(`b mismatch. "2024-06-01T00:00:00Z" vs. "2024-06-01T00:00:01Z"`),
+		}},
+		{"minutes", []Option{WithTimeGranularity(time.Minute)}, `{"a": "2024-06-01T00:05:59Z"}`, `{"a": "2024-05-31T19:05:00-05:00"}`, nil},
+		{"dates", []Option{WithTimeGranularity(DateGranularity)}, `{"a": "2024-06-01", "b": "2024-06-01", "c": "2024-06-01"}`, `{"a": "2024-06-01T00:00:00Z", "b": "2024-06-01T23:30:00-05:00", "c": "2024-06-02T00:30:00+02:00"}`, []error{
+			fmt.Errorf(`c mismatch. "2024-06-01" vs. "2024-06-02T00:30:00+02:00"`),
+		}},
+		{"rule granularity", []Option{WithRules(map[string]Rule{"dob": {Granularity: "date"}, "at": {Granularity: "1m"}})}, `{"dob": "1980-01-02", "at": "2024-06-01T00:00:10Z"}`, `{"dob": "1980-01-02T00:00:00Z", "at": "2024-06-01T00:00:50Z"}`, nil},
+		{"epoch seconds", []Option{WithEpochTimes(time.Second)}, `{"a": 1717200000, "b": 1717200000.5, "c": 1717200000}`, `{"a": "2024-06-01T00:00:00Z", "b": "2024-05-31T19:00:00.5-05:00", "c": "2024-06-01T00:00:01Z"}`, []error{
+			fmt.Errorf(`c mismatch. 1717200000 vs. "2024-06-01T00:00:01Z"`),
+		}},
+		{"epoch milliseconds", []Option{WithEpochTimes(time.Millisecond)}, `{"a": 1717200000123}`, `{"a": "2024-06-01T00:00:00.123Z"}`, nil},
+		{"epoch with granularity", []Option{WithEpochTimes(time.Millisecond), WithTimeGranularity(DateGranularity)}, `{"a": 1717200000123}`, `{"a": "2024-06-01"}`, nil},
+		{"epoch rule", []Option{WithRules(map[string]Rule{"legacy.*": {Epoch: "ms"}})}, `{"legacy": {"at": 1717200000000}, "at": 1717200000000}`, `{"legacy": {"at": "2024-06-01T00:00:00Z"}, "at": "2024-06-01T00:00:00Z"}`, []error{
+			fmt.Errorf(`at mismatch. 1717200000000 vs. "2024-06-01T00:00:00Z"`),
+		}},
+		{"epoch out of range", []Option{WithEpochTimes(time.Second)}, `{"a": 1e300}`, `{"a": "2024-06-01T00:00:00Z"}`, []error{fmt.Errorf(`a mismatch. 1e300 vs. "2024-06-01T00:00:00Z"`)}},
+		{"within", []Option{TimeWithin(5 * time.Second)}, `{"a": "2024-06-01T00:00:00Z", "b": "2024-06-01T00:00:00Z"}`, `{"a": "2024-05-31T19:00:05-05:00", "b": "2024-06-01T00:00:05.001Z"}`, []error{
+			fmt.Errorf(`b mismatch. "2024-06-01T00:00:00Z" vs. "2024-06-01T00:00:05.001Z"`),
+		}},
+		{"within rule", []Option{WithRules(map[string]Rule{"createdAt": {Within: "1m"}})}, `{"createdAt": "2024-06-01T00:00:00Z"}`, `{"createdAt": "2024-06-01T00:00:59Z"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestParseGranularity(t *testing.T) {
+	tests := []struct {
+		value         string
+		expected      time.Duration
+		expectedError string
+	}{
+		{"date", DateGranularity, ""},
+		{"1m", time.Minute, ""},
+		{"-1s", 0, "granularity -1s isn't positive"},
+		{"daily", 0, `time: invalid duration "daily"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			actual, err := parseGranularity(tt.value)
+			var errText string
+			if err != nil {
+				errText = err.Error()
+			}
+			if actual != tt.expected || errText != tt.expectedError {
+				t.Errorf("expected %v %q, got %v %q", tt.expected, tt.expectedError, actual, errText)
+			}
+		})
+	}
+}
+
+func TestMatchNow(t *testing.T) {
+	now := time.Now()
+	m := MatchNow(time.Minute)
+	tests := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{now.Format(time.RFC3339Nano), true},
+		{now.Add(-59 * time.Second).Format(time.RFC3339), true},
+		{now.Add(2 * time.Minute).UTC().Format(time.RFC3339), false},
+		{"now", false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if actual := m.Match(tt.value); actual != tt.expected {
+			t.Errorf("%v: expected %v, got %v", tt.value, tt.expected, actual)
+		}
+	}
+	if m.String() != "a timestamp within 1m0s of now" {
+		t.Errorf("unexpected description %q", m)
+	}
+}
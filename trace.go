@@ -0,0 +1,186 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// TraceOutcome is how a comparison decided that the values at a path are equal or not.
+type TraceOutcome string
+
+const (
+	// TraceEqual is for values that are the same.
+	TraceEqual TraceOutcome = "equal"
+	// TraceLenient is for different values that are considered equal, such as null and "", or
+	// two numbers within the tolerance.
+	TraceLenient TraceOutcome = "lenient-equal"
+	// TraceIgnored is for values that aren't compared because a rule ignores them.
+	TraceIgnored TraceOutcome = "ignored"
+	// TraceMatched is for values accepted by a Matcher or placeholder, or that are scrubbed or
+	// hashed.
+	TraceMatched TraceOutcome = "matched"
+	// TraceMismatch is for values that differ.
+	TraceMismatch TraceOutcome = "mismatch"
+)
+
+// TraceEntry is the outcome of comparing the values at one path, as written by WithTrace.
+type TraceEntry struct {
+	Path    string
+	Outcome TraceOutcome
+	// Reason explains the outcome, such as the rule that ignored the values or the reason
+	// different values are equal. It is empty for TraceEqual.
+	Reason string
+}
+
+func (e TraceEntry) String() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Outcome)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Outcome, e.Reason)
+}
+
+// WithTrace writes a line to w for every path compared, with the outcome of the comparison,
+// which helps to find out why an assertion unexpectedly passed:
+//   claims[0].paidAmount: lenient-equal (within tolerance 0.01)
+//   claims[0].status: equal
+//   meta: ignored (rule for meta)
+//   orderId: matched (matched by regexp "^ord-\\d+$")
+//   total: mismatch (value)
+// Objects and arrays that are compared by their contents are written as the paths in them; the
+// others are written as a whole, like other values.
+func WithTrace(w io.Writer) Option {
+	return func(c *Comparer) {
+		c.trace = func(e TraceEntry) {
+			fmt.Fprintln(w, e)
+		}
+	}
+}
+
+// traceIgnored traces the values at location, which a rule ignores.
+func (c *Comparer) traceIgnored(location string) {
+	if c.trace == nil {
+		return
+	}
+	reason := ""
+	for _, rule := range c.rules {
+		if rule.ignore && rule.pattern.match(location) {
+			reason = "rule for " + rule.pattern.path
+			break
+		}
+	}
+	c.trace(TraceEntry{Path: location, Outcome: TraceIgnored, Reason: reason})
+}
+
+// traceValue traces the comparison of the values at location, which returned errors. decidedBy is
+// the rule returned by compareWithRule. Objects and arrays compared by their contents aren't
+// traced, since the values in them are.
+func (c *Comparer) traceValue(location string, value1, value2 interface{}, rule pathRule, decidedBy string, errors []error) {
+	for _, err := range errors {
+		if m := asMismatch(err); m.Path == location {
+			c.trace(TraceEntry{Path: location, Outcome: TraceMismatch, Reason: string(m.Kind)})
+			return
+		}
+	}
+	switch {
+	case decidedBy == "scrubbed" || decidedBy == "hashed" || strings.HasPrefix(decidedBy, "matched by"):
+		if len(errors) == 0 {
+			c.trace(TraceEntry{Path: location, Outcome: TraceMatched, Reason: decidedBy})
+		}
+	case decidedBy != "":
+		c.trace(c.equalEntry(location, value1, value2, decidedBy))
+	case len(errors) > 0 || hasContents(value1) || hasContents(value2):
+		// the values in them are traced
+	default:
+		c.trace(c.equalEntry(location, value1, value2, c.leniency(value1, value2, rule)))
+	}
+}
+
+// equalEntry traces values considered equal, which are lenient-equal for the reason given
+// unless they are the same.
+func (c *Comparer) equalEntry(location string, value1, value2 interface{}, reason string) TraceEntry {
+	if sameValues(value1, value2) {
+		return TraceEntry{Path: location, Outcome: TraceEqual}
+	}
+	return TraceEntry{Path: location, Outcome: TraceLenient, Reason: reason}
+}
+
+// leniency explains why different values of the given types compared equal under rule.
+func (c *Comparer) leniency(value1, value2 interface{}, rule pathRule) string {
+	switch {
+	case value1 == nil || value2 == nil:
+		return "null and zero value"
+	case jsonType(value1) == "number":
+		if scale, ok := c.decimalScale(rule); ok {
+			return fmt.Sprintf("rounded to %d decimal places", scale)
+		}
+		tolerance, percent := c.numericTolerance(rule)
+		if tolerance > 0 {
+			return fmt.Sprintf("within tolerance %v", tolerance)
+		}
+		if percent > 0 {
+			return fmt.Sprintf("within %v percent", percent)
+		}
+		return "equal numbers"
+	case jsonType(value1) == "string":
+		if rule.ignoreCase {
+			return "ignoring case"
+		}
+		return "normalized unicode"
+	}
+	return ""
+}
+
+// sameValues reports whether value1 and value2 are the same JSON value, so that comparing them
+// needed no leniency. Numbers are the same if they are equal, however they are written.
+func sameValues(value1, value2 interface{}) bool {
+	n1, ok1 := value1.(json.Number)
+	n2, ok2 := value2.(json.Number)
+	if ok1 && ok2 {
+		r1, exact1 := exactNumber(n1)
+		r2, exact2 := exactNumber(n2)
+		return n1 == n2 || exact1 && exact2 && r1.Cmp(r2) == 0 && (r1.Sign() != 0 || n1[0] == n2[0])
+	}
+	return reflect.DeepEqual(value1, value2)
+}
+
+// hasContents reports whether v is an object or array that isn't empty.
+func hasContents(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// AuditLeniency compares json1 and json2, which may hold any JSON value, with the options, and
+// returns every place where the documents only compared equal because of a lenient rule: values
+// that are lenient-equal, such as null and "" or numbers within the tolerance, values ignored
+// by a rule, and values accepted by a Matcher. Running it over the fixtures from time to time
+// shows how much the tests rely on leniency, and where they can be tightened:
+//   for _, e := range jsonassert.AuditLeniency(fixture, encoded) {
+//     fmt.Println(e)
+//   }
+// Differences between the documents aren't included.
+func AuditLeniency(json1, json2 []byte, opts ...Option) []TraceEntry {
+	return NewComparer(opts...).AuditLeniency(json1, json2)
+}
+
+// AuditLeniency is like the package-level AuditLeniency, but compares the JSON using the
+// options of the Comparer.
+func (c *Comparer) AuditLeniency(json1, json2 []byte) []TraceEntry {
+	var entries []TraceEntry
+	auditing := c.With()
+	auditing.trace = func(e TraceEntry) {
+		switch e.Outcome {
+		case TraceLenient, TraceIgnored, TraceMatched:
+			entries = append(entries, e)
+		}
+	}
+	auditing.equalValues(json1, json2)
+	return entries
+}
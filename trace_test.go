@@ -0,0 +1,84 @@
+package jsonassert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithTrace(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		json1 string
+		json2 string
+		trace string
+	}{
+		{
+			name:  "leaves",
+			json1: `{"a": 1, "b": "", "c": [1.0, true], "d": {"e": null}}`,
+			json2: `{"a": 1.0, "c": [1, true], "d": {"e": "x"}}`,
+			trace: "a: equal\nb: lenient-equal (null and zero value)\nc[0]: equal\nc[1]: equal\nd.e: mismatch (extra)\n",
+		},
+		{
+			name:  "rules",
+			opts:  []Option{WithIgnorePaths("meta"), WithTolerance(0.1), WithIgnoreCase("s"), WithMatcher("id", MatchRegexp("^x"))},
+			json1: `{"n": 1, "s": "A", "id": "x1", "meta": {"v": 1}}`,
+			json2: `{"n": 1.05, "s": "a", "id": "x2", "meta": {"v": 2}}`,
+			trace: "id: matched (matched by regexp \"^x\")\nmeta: ignored (rule for meta)\nn: lenient-equal (within tolerance 0.1)\ns: lenient-equal (ignoring case)\n",
+		},
+		{
+			name:  "whole values",
+			opts:  []Option{WithNumericStrings("n"), StrictPresence()},
+			json1: `{"a": [1], "n": "2", "o": {}, "p": 1}`,
+			json2: `{"a": [1, 2], "n": 2, "o": null}`,
+			trace: "a: mismatch (length)\nn: lenient-equal (numeric strings)\no: lenient-equal (null and zero value)\np: mismatch (missing)\n",
+		},
+		{
+			name:  "keyed arrays",
+			opts:  []Option{WithArrayKey("items", "id")},
+			json1: `{"items": [{"id": 1}, {"id": 2}]}`,
+			json2: `{"items": [{"id": 2}]}`,
+			trace: "items[0]: mismatch (missing)\nitems[1].id: equal\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace strings.Builder
+			NewComparer(append(tt.opts, WithTrace(&trace))...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			if trace.String() != tt.trace {
+				t.Errorf("trace = %q, want %q", trace.String(), tt.trace)
+			}
+		})
+	}
+}
+
+func TestAuditLeniency(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		json1   string
+		json2   string
+		entries []TraceEntry
+	}{
+		{"strict equal", nil, `{"a": 1, "b": [true]}`, `{"a": 1.0, "b": [true]}`, nil},
+		{"lenient", []Option{WithIgnorePaths("meta.*"), WithTolerance(0.01), WithMatcher("id", MatchRegexp("^x"))},
+			`{"a": "", "b": 1, "c": 2, "id": "x1", "meta": {"t": 1}}`,
+			`{"b": 1.001, "c": 3, "id": "x2", "meta": {"t": 2}}`,
+			[]TraceEntry{
+				{Path: "a", Outcome: TraceLenient, Reason: "null and zero value"},
+				{Path: "b", Outcome: TraceLenient, Reason: "within tolerance 0.01"},
+				{Path: "id", Outcome: TraceMatched, Reason: `matched by regexp "^x"`},
+				{Path: "meta.t", Outcome: TraceIgnored, Reason: "rule for meta.*"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := AuditLeniency([]byte(tt.json1), []byte(tt.json2), tt.opts...)
+			if !reflect.DeepEqual(entries, tt.entries) {
+				t.Errorf("AuditLeniency() = %v, want %v", entries, tt.entries)
+			}
+		})
+	}
+}
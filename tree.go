@@ -0,0 +1,147 @@
+package jsonassert
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WriteTree renders the two JSON documents merged into a single tree and writes it to w. Only the
+// branches that contain differences are expanded. Runs of equal keys or elements are collapsed into
+// a single line such as "… (12 keys equal)" so that large documents with a few differences stay
+// readable. Differing leaf values are printed the same way as in the errors returned by EqualMap.
+func WriteTree(w io.Writer, json1, json2 []byte) error {
+	return NewComparer().WriteTree(w, json1, json2)
+}
+
+// WriteTree is like the package-level WriteTree, but decides which values are equal using the
+// options of the Comparer.
+func (c *Comparer) WriteTree(w io.Writer, json1, json2 []byte) error {
+	value1, err := c.getJSONValue(json1)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json1: %v", err)
+	}
+	value2, err := c.getJSONValue(json2)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json2: %v", err)
+	}
+	var sb strings.Builder
+	c.writeTreeValue(&sb, "", "", value1, value2)
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+func (c *Comparer) getJSONValue(text []byte) (interface{}, error) {
+	var value interface{}
+	return value, c.unmarshal(text, &value)
+}
+
+func (c *Comparer) writeTreeValue(sb *strings.Builder, indent, location string, value1, value2 interface{}) {
+	map1, isMap1 := value1.(map[string]interface{})
+	map2, isMap2 := value2.(map[string]interface{})
+	if (isMap1 || isMap2) && (isMap1 || value1 == nil) && (isMap2 || value2 == nil) {
+		sb.WriteString("{\n")
+		c.writeTreeMap(sb, indent+"  ", location, map1, map2)
+		sb.WriteString(indent + "}\n")
+		return
+	}
+	if pairs, ok := keyedPairs(value1, value2, c.ruleFor(location).arrayKey); ok {
+		sb.WriteString("[\n")
+		c.writeTreeKeyedSlice(sb, indent+"  ", location, pairs)
+		sb.WriteString(indent + "]\n")
+		return
+	}
+	rv1, rv2 := reflect.ValueOf(value1), reflect.ValueOf(value2)
+	if rv1.Kind() == reflect.Slice && rv2.Kind() == reflect.Slice && rv1.Len() == rv2.Len() {
+		sb.WriteString("[\n")
+		c.writeTreeSlice(sb, indent+"  ", location, rv1, rv2)
+		sb.WriteString(indent + "]\n")
+		return
+	}
+	fmt.Fprintf(sb, "%s vs. %s\n", c.quoteValue(location, value1), c.quoteValue(location, value2))
+}
+
+func (c *Comparer) writeTreeMap(sb *strings.Builder, indent, location string, map1, map2 map[string]interface{}) {
+	equal := 0
+	for _, key := range mergedKeys(map1, map2) {
+		if _, ok := map1[key]; !ok && c.allowExtraKeys {
+			continue
+		}
+		keyLocation := getLocation(location, key)
+		if len(c.compareKey(location, key, map1, map2)) == 0 {
+			equal++
+			continue
+		}
+		writeTreeElided(sb, indent, equal, "key")
+		equal = 0
+		sb.WriteString(indent + key + ": ")
+		value1, ok1 := map1[key]
+		value2, ok2 := map2[key]
+		if c.strictPresence && ok1 != ok2 {
+			fmt.Fprintf(sb, "%s vs. %s\n", c.presentValue(keyLocation, value1, ok1), c.presentValue(keyLocation, value2, ok2))
+			continue
+		}
+		c.writeTreeValue(sb, indent, keyLocation, value1, value2)
+	}
+	writeTreeElided(sb, indent, equal, "key")
+}
+
+func (c *Comparer) writeTreeSlice(sb *strings.Builder, indent, location string, rv1, rv2 reflect.Value) {
+	equal := 0
+	for i := 0; i < rv1.Len(); i++ {
+		indexLocation := fmt.Sprintf("%s[%d]", location, i)
+		value1, value2 := rv1.Index(i).Interface(), rv2.Index(i).Interface()
+		if len(c.compareValues(indexLocation, value1, value2)) == 0 {
+			equal++
+			continue
+		}
+		writeTreeElided(sb, indent, equal, "element")
+		equal = 0
+		fmt.Fprintf(sb, "%s[%d]: ", indent, i)
+		c.writeTreeValue(sb, indent, indexLocation, value1, value2)
+	}
+	writeTreeElided(sb, indent, equal, "element")
+}
+
+func (c *Comparer) writeTreeKeyedSlice(sb *strings.Builder, indent, location string, pairs []slicePair) {
+	equal := 0
+	for _, pair := range pairs {
+		indexLocation := location + pair.index
+		if len(c.compareKeyedSlices(location, []slicePair{pair})) == 0 {
+			equal++
+			continue
+		}
+		writeTreeElided(sb, indent, equal, "element")
+		equal = 0
+		sb.WriteString(indent + pair.index + ": ")
+		if pair.value1 == nil || pair.value2 == nil {
+			fmt.Fprintf(sb, "%s vs. %s\n", c.quoteValue(indexLocation, pair.value1), c.quoteValue(indexLocation, pair.value2))
+			continue
+		}
+		c.writeTreeValue(sb, indent, indexLocation, pair.value1, pair.value2)
+	}
+	writeTreeElided(sb, indent, equal, "element")
+}
+
+func writeTreeElided(sb *strings.Builder, indent string, count int, noun string) {
+	if count == 0 {
+		return
+	}
+	if count > 1 {
+		noun += "s"
+	}
+	fmt.Fprintf(sb, "%s… (%d %s equal)\n", indent, count, noun)
+}
+
+func mergedKeys(map1, map2 map[string]interface{}) []string {
+	merged := keys(map1)
+	for _, key := range keys(map2) {
+		if _, ok := map1[key]; !ok {
+			merged = append(merged, key)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,77 @@
+package jsonassert
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		json1    string
+		json2    string
+		expected string
+	}{
+		{"same string", jsonComplete, jsonComplete, "{\n  … (10 keys equal)\n}\n"},
+		{"missing on the right", jsonComplete, jsonMissingStrings, `{
+  arr: [1 2 3] vs. [1 2]
+  … (5 keys equal)
+  obj: {
+    … (1 key equal)
+    b: "val2" vs. <nil>
+  }
+  … (1 key equal)
+  str: "2" vs. <nil>
+  … (1 key equal)
+}
+`},
+		{"slice", `[{"a": 1, "b": 2}, {"a": 3}]`, `[{"a": 1, "b": 2}, {"a": 4}]`, `[
+  … (1 element equal)
+  [1]: {
+    a: 3 vs. 4
+  }
+]
+`},
+		{"with children", `{"a": null}`, `{"a": {"1":"", "2":"b"}}`, `{
+  a: {
+    … (1 key equal)
+    2: <nil> vs. "b"
+  }
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteTree(&buf, []byte(tt.json1), []byte(tt.json2)); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("tree mismatch. want:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteTreeStrictPresence(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewComparer(StrictPresence()).WriteTree(&buf, []byte(`{"a": null, "b": 1}`), []byte(`{"b": 1, "c": {}}`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  a: <nil> vs. <absent>
+  … (1 key equal)
+  c: <absent> vs. map[]
+}
+`
+	if buf.String() != expected {
+		t.Errorf("tree mismatch. want:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteTreeInvalid(t *testing.T) {
+	err := WriteTree(&bytes.Buffer{}, []byte(`{`), []byte(jsonComplete))
+	if err == nil || err.Error() != "error unmarshalling json1: unexpected end of JSON input" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package jsonassert
+
+import (
+	"encoding/json"
+)
+
+// EqualT encodes expected and actual to JSON and compares them with the options, reporting any
+// differences to t as StructCheck does, and returns whether they are equal. It is a type-safe
+// alternative to encoding values and passing the bytes to EqualMap or EqualSlice: the values
+// are compared with JSON semantics, so a nil slice is equal to an empty one and fields that
+// are omitted when empty are equal to their zero values. T may be any type that encodes to
+// JSON, including a scalar.
+func EqualT[T any](t Testing, expected, actual T, opts ...Option) bool {
+	t.Helper()
+	c := NewComparer(opts...)
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	json1, err := json.Marshal(expected)
+	if err != nil {
+		t.Errorf("error encoding expected: %v", err)
+		return false
+	}
+	json2, err := json.Marshal(actual)
+	if err != nil {
+		t.Errorf("error encoding actual: %v", err)
+		return false
+	}
+	errors := c.equalValues(json1, json2)
+	c.notifyErrors(t, "actual", errors)
+	c.notifyDiff(t, json1, json2, errors)
+	return len(errors) == 0
+}
@@ -0,0 +1,83 @@
+package jsonassert
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+type typedItem struct {
+	Name  string   `json:"name"`
+	Price float64  `json:"price"`
+	Tags  []string `json:"tags"`
+}
+
+func TestEqualT(t *testing.T) {
+	tests := []struct {
+		name           string
+		check          func(t Testing) bool
+		expectedErrors []error
+	}{
+		{
+			name: "equal structs",
+			check: func(t Testing) bool {
+				return EqualT(t, typedItem{Name: "a", Tags: []string{}}, typedItem{Name: "a"})
+			},
+		},
+		{
+			name: "struct mismatch",
+			check: func(t Testing) bool {
+				return EqualT(t, typedItem{Name: "a", Price: 1.5}, typedItem{Name: "b", Price: 1.5})
+			},
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf(`name mismatch. "a" vs. "b"`),
+			},
+		},
+		{
+			name: "options",
+			check: func(t Testing) bool {
+				return EqualT(t, typedItem{Price: 1.5}, typedItem{Price: 1.501}, WithTolerance(0.01))
+			},
+		},
+		{
+			name: "slices",
+			check: func(t Testing) bool {
+				return EqualT(t, []typedItem{{Name: "a"}}, []typedItem{{Name: "b"}})
+			},
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf(`[0].name mismatch. "a" vs. "b"`),
+			},
+		},
+		{
+			name: "scalars",
+			check: func(t Testing) bool {
+				return EqualT(t, 1, 2)
+			},
+			expectedErrors: []error{
+				fmt.Errorf("*** 1 errors in actual"),
+				fmt.Errorf(" mismatch. 1 vs. 2"),
+			},
+		},
+		{
+			name: "unencodable",
+			check: func(t Testing) bool {
+				return EqualT(t, math.Inf(1), 1)
+			},
+			expectedErrors: []error{
+				fmt.Errorf("error encoding expected: json: unsupported value: +Inf"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			equal := tt.check(fakeT)
+			if equal != (len(tt.expectedErrors) == 0) {
+				t.Errorf("EqualT() = %v with errors %v", equal, fakeT.errors)
+			}
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package jsonassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RejectDuplicateKeys reports objects that contain the same key more than once as errors.
+// encoding/json silently keeps the last of the duplicated values, so without this option a
+// document with a duplicated key can be equal to one that has only one of its values. The error
+// names the location of each duplicated key in the format set by WithPathFormat, such as
+// "duplicate key items[0].id".
+func RejectDuplicateKeys() Option {
+	return func(c *Comparer) {
+		c.rejectDuplicateKeys = true
+	}
+}
+
+// RejectInvalidUTF8 reports byte sequences that aren't valid UTF-8 as errors that give their
+// byte offsets in the document. encoding/json replaces them with U+FFFD, so without this option
+// a corrupted document can be equal to another corrupted in a different way.
+func RejectInvalidUTF8() Option {
+	return func(c *Comparer) {
+		c.rejectInvalidUTF8 = true
+	}
+}
+
+// WithMaxSize rejects documents larger than size bytes with an error such as "document exceeds
+// 10 MB". StructCheck, NullCheck and OmitZeroCheck check the size of a file before reading it,
+// so a fixture path that accidentally matches a database dump fails fast instead of exhausting
+// memory. NullCheck and OmitZeroCheck use the size set by SetDefaultOptions. A size of 0 allows
+// documents of any size, which is the default.
+func WithMaxSize(size int64) Option {
+	return func(c *Comparer) {
+		c.maxSize = size
+	}
+}
+
+// formatSize writes a number of bytes in the largest unit that divides it evenly.
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", size>>20)
+	case size >= 1<<10 && size%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", size>>10)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+// readFile reads filename like os.ReadFile, but fails without reading it if it's larger than
+// the size set by WithMaxSize.
+func (c *Comparer) readFile(filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return c.readOpenFile(f, filename)
+}
+
+// readFileFS is like readFile, but reads filename from fsys.
+func (c *Comparer) readFileFS(fsys fs.FS, filename string) ([]byte, error) {
+	f, err := fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return c.readOpenFile(f, filename)
+}
+
+// readOpenFile reads and closes f, which was opened from filename, for readFile and readFileFS.
+func (c *Comparer) readOpenFile(f fs.File, filename string) ([]byte, error) {
+	defer f.Close()
+	if c.maxSize <= 0 {
+		return io.ReadAll(f)
+	}
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > c.maxSize {
+		return nil, fmt.Errorf("%s exceeds %s", filename, formatSize(c.maxSize))
+	}
+	text, err := io.ReadAll(io.LimitReader(f, c.maxSize+1)) // for files whose size isn't known
+	if err == nil && int64(len(text)) > c.maxSize {
+		return nil, fmt.Errorf("%s exceeds %s", filename, formatSize(c.maxSize))
+	}
+	return text, err
+}
+
+// maxDepth is the deepest nesting of arrays and objects the Comparer accepts. Comparing a value
+// recurses once for each level, so deeper documents are rejected before they are decoded rather
+// than risk exhausting the stack.
+const maxDepth = 1000
+
+// checkText checks text for the problems the Comparer rejects before decoding it. Unlike
+// validate, it checks the text as written, so that offsets refer to the original document.
+func (c *Comparer) checkText(text []byte) error {
+	if c.maxSize > 0 && int64(len(text)) > c.maxSize {
+		return fmt.Errorf("document exceeds %s", formatSize(c.maxSize))
+	}
+	if offset := depthExceeded(text, maxDepth); offset >= 0 {
+		return fmt.Errorf("arrays and objects are nested more than %d levels deep at byte offset %d", maxDepth, offset)
+	}
+	if !c.rejectInvalidUTF8 {
+		return nil
+	}
+	offsets := invalidUTF8Offsets(text)
+	switch len(offsets) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("invalid UTF-8 at byte offset %d", offsets[0])
+	}
+	s := make([]string, len(offsets))
+	for i, offset := range offsets {
+		s[i] = strconv.Itoa(offset)
+	}
+	return fmt.Errorf("invalid UTF-8 at byte offsets %s", strings.Join(s, ", "))
+}
+
+// invalidUTF8Offsets returns the offsets of the bytes in text that aren't part of a valid UTF-8
+// encoded character.
+func invalidUTF8Offsets(text []byte) []int {
+	var offsets []int
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRune(text[i:])
+		if r == utf8.RuneError && size == 1 {
+			offsets = append(offsets, i)
+		}
+		i += size
+	}
+	return offsets
+}
+
+// depthExceeded returns the offset of the bracket in text that nests arrays and objects more than
+// limit levels deep, or -1 if there is none. Brackets in strings are skipped.
+func depthExceeded(text []byte, limit int) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch ch := text[i]; {
+		case inString:
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '[' || ch == '{':
+			if depth++; depth > limit {
+				return i
+			}
+		case ch == ']' || ch == '}':
+			depth--
+		}
+	}
+	return -1
+}
+
+// AllowConcatenated accepts documents that hold a stream of concatenated JSON values, such as
+// JSON Lines, and compares them as arrays of those values, so they can be compared with
+// EqualSlice. A document holding a single value is compared as that value. StructCheck decodes
+// each value of such a document into an element of its slice result. Without this option,
+// content after the first value of a document is reported as an error.
+func AllowConcatenated() Option {
+	return func(c *Comparer) {
+		c.allowConcatenated = true
+	}
+}
+
+// concatenatedArray returns text as a JSON array of its values if it holds more than one JSON
+// value, and otherwise returns text unchanged.
+func concatenatedArray(text []byte) []byte {
+	values, err := splitValues(text)
+	if err != nil || len(values) < 2 {
+		return text
+	}
+	array := [][]byte{[]byte("[")}
+	for i, value := range values {
+		if i > 0 {
+			array = append(array, []byte(","))
+		}
+		array = append(array, value)
+	}
+	return bytes.Join(append(array, []byte("]")), nil)
+}
+
+// splitValues returns the JSON values concatenated in text.
+func splitValues(text []byte) ([]json.RawMessage, error) {
+	d := json.NewDecoder(bytes.NewReader(text))
+	var values []json.RawMessage
+	for {
+		var value json.RawMessage
+		if err := d.Decode(&value); err == io.EOF {
+			return values, nil
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
+
+// decode decodes the JSON in text into result like json.Unmarshal, or the Codec set by
+// WithCodec, except that if the Comparer allows concatenated values and text holds more than
+// one, each is decoded into an element of the slice result points to.
+func (c *Comparer) decode(text []byte, result interface{}) error {
+	text = c.stripJSONC(text)
+	if c.allowConcatenated {
+		if values, err := splitValues(text); err == nil && len(values) > 1 {
+			return c.decodeValues(values, result)
+		}
+	}
+	if err := c.newDecoder(text).Decode(result); err != nil {
+		return err
+	}
+	if !json.Valid(text) { // the first value is valid, so there is content after it
+		return explainSyntaxError(text, json.Unmarshal(text, new(interface{})))
+	}
+	return nil
+}
+
+func (c *Comparer) decodeValues(values []json.RawMessage, result interface{}) error {
+	slice := reflect.ValueOf(result).Elem()
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("found %d concatenated JSON values, which can only be decoded into a slice, but got %T", len(values), result)
+	}
+	slice.Set(slice.Slice(0, 0))
+	for _, value := range values {
+		element := reflect.New(slice.Type().Elem())
+		if err := c.unmarshalValue(value, element.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, element.Elem()))
+	}
+	return nil
+}
+
+// validate checks the syntactically valid JSON in text for the problems the Comparer rejects.
+func (c *Comparer) validate(text []byte) error {
+	if !c.rejectDuplicateKeys {
+		return nil
+	}
+	duplicates, err := duplicateKeys(text)
+	if err != nil {
+		return err
+	}
+	for i, location := range duplicates {
+		duplicates[i] = c.formatPath(location)
+	}
+	switch len(duplicates) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("duplicate key %s", duplicates[0])
+	}
+	return fmt.Errorf("duplicate keys %s", strings.Join(duplicates, ", "))
+}
+
+// duplicateKeys returns the locations of the keys in text that repeat an earlier key of the same
+// object, in the order they appear. A key that appears more than twice is only returned once.
+func duplicateKeys(text []byte) ([]string, error) {
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	var duplicates []string
+	var scan func(location string) error
+	scan = func(location string) error {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			seen := map[string]int{}
+			for d.More() {
+				token, err := d.Token()
+				if err != nil {
+					return err
+				}
+				key := token.(string)
+				keyLocation := getLocation(location, key)
+				if seen[key]++; seen[key] == 2 {
+					duplicates = append(duplicates, keyLocation)
+				}
+				if err := scan(keyLocation); err != nil {
+					return err
+				}
+			}
+		case json.Delim('['):
+			for i := 0; d.More(); i++ {
+				if err := scan(fmt.Sprintf("%s[%d]", location, i)); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+		_, err = d.Token() // the closing delimiter
+		return err
+	}
+	return duplicates, scan("")
+}
@@ -0,0 +1,191 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRejectDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"last value wins by default", nil, `{"a": 1, "a": 2}`, `{"a": 2}`, nil},
+		{"no duplicates", []Option{RejectDuplicateKeys()}, `{"a": 1, "b": {"a": 1}, "c": [{"a": 1}, {"a": 2}]}`, `{"a": 1, "b": {"a": 1}, "c": [{"a": 1}, {"a": 2}]}`, nil},
+		{"duplicate", []Option{RejectDuplicateKeys()}, `{"a": 1, "a": 2}`, `{"a": 2}`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate key a"),
+		}},
+		{"nested duplicates", []Option{RejectDuplicateKeys()}, `{"a": {"b": 1, "b": 1, "b": 1}, "items": [{}, {"id": 1, "x": [], "id": 2}]}`, `{"c": 1, "c": 1}`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate keys a.b, items[1].id"),
+			fmt.Errorf("error unmarshalling json2: duplicate key c"),
+		}},
+		{"pointer paths", []Option{RejectDuplicateKeys(), WithPathFormat(PathPointer)}, `{"a.b": {"c": 1, "c": 2}, "items": [{"id": 1, "id": 2}]}`, `{"a/b": 1, "a/b": 1}`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate keys /a.b/c, /items/0/id"),
+			fmt.Errorf("error unmarshalling json2: duplicate key /a~1b"),
+		}},
+		{"with literals", []Option{RejectDuplicateKeys(), AllowNonFinite()}, `{"a": NaN, "a": 1}`, `{"a": 1}`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate key a"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestRejectDuplicateKeysSlice(t *testing.T) {
+	c := NewComparer(RejectDuplicateKeys())
+	checkErrors(t, []error{fmt.Errorf("error unmarshalling json2: duplicate key [1].a")}, c.EqualSlice([]byte(`[1, {"a": 1}]`), []byte(`[1, {"a": 1, "a": 1}]`)))
+}
+
+func TestAllowConcatenated(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"rejected by default", nil, `[1] [2]`, `[1] x`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character '[' after top-level value (concatenated values are accepted by AllowConcatenated)"),
+			fmt.Errorf("error unmarshalling json2: invalid character 'x' after top-level value"),
+		}},
+		{"equal", []Option{AllowConcatenated()}, "{\"a\": 1}\n{\"a\": 2}\n", `[{"a": 1}, {"a": 2}]`, nil},
+		{"single value", []Option{AllowConcatenated()}, `[1, 2]`, `[1, 2]`, nil},
+		{"different", []Option{AllowConcatenated()}, `{"a": 1} {"a": 2}`, `{"a": 1} {"a": 3}`, []error{
+			fmt.Errorf("[1].a mismatch. 2 vs. 3"),
+		}},
+		{"trailing garbage", []Option{AllowConcatenated()}, `{"a": 1} x`, `[]`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid character 'x' after top-level value"),
+		}},
+		{"duplicate keys", []Option{AllowConcatenated(), RejectDuplicateKeys()}, `{"a": 1} {"a": 1, "a": 2}`, `[]`, []error{
+			fmt.Errorf("error unmarshalling json1: duplicate key [1].a"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualSlice([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestStructCheckConcatenated(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparer       *Comparer
+		filename       string
+		result         interface{}
+		expectedErrors []error
+	}{
+		{"trailing data", NewComparer(), "testdata/stream/trailing.json", &sliceStruct{}, []error{
+			fmt.Errorf("error decoding json in testdata/stream/trailing.json: invalid character '{' after top-level value (concatenated values are accepted by AllowConcatenated)"),
+		}},
+		{"stream", NewComparer(AllowConcatenated()), "testdata/stream/items.jsonl", &[]sliceStruct{}, nil},
+		{"stream into a struct", NewComparer(AllowConcatenated()), "testdata/stream/items.jsonl", &sliceStruct{}, []error{
+			fmt.Errorf("error decoding json in testdata/stream/items.jsonl: found 2 concatenated JSON values, which can only be decoded into a slice, but got *jsonassert.sliceStruct"),
+		}},
+		{"single value", NewComparer(AllowConcatenated()), "testdata/array.json", &[]sliceStruct{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			tt.comparer.StructCheck(fakeT, tt.filename, tt.result)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
+
+func TestRejectInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"replaced by default", nil, "{\"a\": \"x\xff\"}", "{\"a\": \"x\xfe\"}", nil},
+		{"valid", []Option{RejectInvalidUTF8()}, `{"a": "é", "b": "😀"}`, `{"a": "é", "b": "😀"}`, nil},
+		{"invalid", []Option{RejectInvalidUTF8()}, "{\"a\": \"x\xff\"}", "{\"a\": \"\xc3\", \"b\": \"\xed\xa0\x80\"}", []error{
+			fmt.Errorf("error unmarshalling json1: invalid UTF-8 at byte offset 8"),
+			fmt.Errorf("error unmarshalling json2: invalid UTF-8 at byte offsets 7, 17, 18, 19"),
+		}},
+		{"offsets in the original text", []Option{RejectInvalidUTF8(), AllowNonFinite()}, "{\"a\": NaN, \"b\": \"\xff\"}", `{}`, []error{
+			fmt.Errorf("error unmarshalling json1: invalid UTF-8 at byte offset 17"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer(tt.opts...).EqualMap([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("[", depth) + strings.Repeat("]", depth)
+	}
+	tests := []struct {
+		name           string
+		json1          string
+		json2          string
+		expectedErrors []error
+	}{
+		{"at the limit", nested(maxDepth), nested(maxDepth), nil},
+		{"brackets in strings", `["[[[\"[", "{"]`, `["[[[\"[", "{"]`, nil},
+		{"too deep", nested(maxDepth + 1), `[{"a": ` + nested(maxDepth) + `}]`, []error{
+			fmt.Errorf("error unmarshalling json1: arrays and objects are nested more than 1000 levels deep at byte offset 1000"),
+			fmt.Errorf("error unmarshalling json2: arrays and objects are nested more than 1000 levels deep at byte offset 1005"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewComparer().EqualSlice([]byte(tt.json1), []byte(tt.json2))
+			checkErrors(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestWithMaxSize(t *testing.T) {
+	c := NewComparer(WithMaxSize(8))
+	checkErrors(t, nil, c.EqualMap([]byte(`{"a": 1}`), []byte(`{"a":1}`)))
+	checkErrors(t, []error{fmt.Errorf("error unmarshalling json1: document exceeds 8 bytes")}, c.EqualMap([]byte(`{"a": 10}`), []byte(`{"a":10}`)))
+
+	fakeT := &fakeTester{}
+	c.StructCheck(fakeT, "testdata/complete.json", &receiveStruct{})
+	checkErrors(t, []error{fmt.Errorf("testdata/complete.json exceeds 8 bytes")}, fakeT.errors)
+
+	SetDefaultOptions(WithMaxSize(1 << 10))
+	defer SetDefaultOptions()
+	fakeT = &fakeTester{}
+	NullCheck(fakeT, "testdata/complete.json", &receiveStruct{})
+	checkErrors(t, nil, fakeT.errors)
+	SetDefaultOptions(WithMaxSize(8))
+	NullCheck(fakeT, "testdata/complete.json", &receiveStruct{})
+	checkErrors(t, []error{fmt.Errorf("testdata/complete.json exceeds 8 bytes")}, fakeT.errors)
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{0, "0 bytes"},
+		{1000, "1000 bytes"},
+		{1 << 10, "1 KB"},
+		{1536 << 10, "1536 KB"},
+		{10 << 20, "10 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.expected {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.expected)
+		}
+	}
+}
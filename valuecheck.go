@@ -0,0 +1,54 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueCheck is like StructCheck, but starts from the Go value v rather than a fixture, to test
+// that custom MarshalJSON and UnmarshalJSON methods are symmetric:
+//   jsonassert.ValueCheck(t, Claim{ID: "1", Billed: decimal.RequireFromString("10.50")})
+// It encodes v, decodes the JSON into a new value of the same type, encodes that value and
+// compares the two encodings as EqualMap does, so anything the first encoding holds that the
+// decoded value loses or changes is reported. If v is a pointer, the JSON is decoded into a new
+// value of the type it points to. The values are encoded and decoded with encoding/json, or as
+// set by WithMarshal and WithCodec.
+func ValueCheck(t Testing, v interface{}) {
+	t.Helper()
+	NewComparer().ValueCheck(t, v)
+}
+
+// ValueCheck is like the package-level ValueCheck, but compares the JSON using the options of
+// the Comparer.
+func (c *Comparer) ValueCheck(t Testing, v interface{}) {
+	t.Helper()
+	t, stop := c.stopOnFailure(t)
+	defer stop()
+	if v == nil {
+		t.Error("invalid argument: ValueCheck needs a value, but got nil")
+		return
+	}
+	name := fmt.Sprintf("%T", v)
+	text, err := c.encode(v)
+	if err != nil {
+		t.Errorf("error encoding %s: %v", name, err)
+		return
+	}
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	decoded := reflect.New(typ).Interface()
+	if err := c.unmarshalValue(text, decoded); err != nil {
+		t.Errorf("error decoding %s: %v", name, err)
+		return
+	}
+	roundTripped, err := c.encode(decoded)
+	if err != nil {
+		t.Errorf("error encoding decoded %s: %v", name, err)
+		return
+	}
+	errors := c.equalValues(text, roundTripped)
+	c.notifyErrors(t, name, errors)
+	c.notifyDiff(t, text, roundTripped, errors)
+}
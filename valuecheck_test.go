@@ -0,0 +1,63 @@
+package jsonassert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// truncatingAmount writes its cents as a decimal amount, but reads only the whole units back.
+type truncatingAmount struct {
+	Cents int64
+}
+
+func (a truncatingAmount) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"amount": %d.%02d}`, a.Cents/100, a.Cents%100)), nil
+}
+
+func (a *truncatingAmount) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSuffix(strings.TrimPrefix(string(data), `{"amount":`), "}")
+	whole, _, _ := strings.Cut(text, ".")
+	units, err := strconv.ParseInt(whole, 10, 64)
+	a.Cents = units * 100
+	return err
+}
+
+// unreadable can be written but not read.
+type unreadable struct{}
+
+func (unreadable) MarshalJSON() ([]byte, error) {
+	return []byte(`"x"`), nil
+}
+
+func (*unreadable) UnmarshalJSON([]byte) error {
+	return fmt.Errorf("unreadable can't be decoded")
+}
+
+func TestValueCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		v              interface{}
+		expectedErrors []error
+	}{
+		{"struct", receiveStruct{Num: 1, Str: "2", Arr: []string{"1"}, Obj: subStruct{A: "val"}}, nil},
+		{"pointer", &receiveStruct{Str: "2"}, nil},
+		{"slice", []sliceStruct{{Item1: "a"}, {Item2: "b"}}, nil},
+		{"lossy unmarshal", truncatingAmount{Cents: 1050}, []error{
+			fmt.Errorf("*** 1 errors in jsonassert.truncatingAmount"),
+			fmt.Errorf("amount mismatch. 10.50 vs. 10.00"),
+		}},
+		{"lossy unmarshal in a pointer", &truncatingAmount{Cents: 1000}, nil},
+		{"encoding error", map[string]interface{}{"c": make(chan int)}, []error{fmt.Errorf("error encoding map[string]interface {}: json: unsupported type: chan int")}},
+		{"decoding error", unreadable{}, []error{fmt.Errorf("error decoding jsonassert.unreadable: unreadable can't be decoded")}},
+		{"nil", nil, []error{fmt.Errorf("invalid argument: ValueCheck needs a value, but got nil")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &fakeTester{}
+			ValueCheck(fakeT, tt.v)
+			checkErrors(t, tt.expectedErrors, fakeT.errors)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package jsonassert
+
+import (
+	"fmt"
+)
+
+// WalkAction tells Walk how to continue after visiting a path.
+type WalkAction int
+
+const (
+	// WalkContinue visits the children of the values at the path.
+	WalkContinue WalkAction = iota
+	// WalkSkipChildren skips the children of the values at the path.
+	WalkSkipChildren
+	// WalkStop ends the walk.
+	WalkStop
+)
+
+// Walk traverses json1 and json2 together and calls fn for each path in either document, with
+// the values at that path in each. The root is visited first, with the path "", followed by the
+// children of each value in the notation of the error messages, such as "a.b[0]". Object keys
+// are visited in sorted order and array elements in index order. A value that is missing from
+// one document is passed as nil, as is a null. When the values at a path are an object and a
+// non-object, the keys of the object are visited with nil for the other document, and likewise
+// for arrays. Walk lets callers build their own analyses on the same traversal the comparison
+// uses, such as counting or extracting values; it returns an error only if a document can't be
+// unmarshalled.
+func Walk(json1, json2 []byte, fn func(path string, v1, v2 interface{}) WalkAction) error {
+	return NewComparer().Walk(json1, json2, fn)
+}
+
+// Walk is like the package-level Walk, but reads the documents using the options of the
+// Comparer, pairs array elements by the keys set for them, and skips the paths it ignores.
+func (c *Comparer) Walk(json1, json2 []byte, fn func(path string, v1, v2 interface{}) WalkAction) error {
+	value1, err := c.getJSONValue(json1)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json1: %v", err)
+	}
+	value2, err := c.getJSONValue(json2)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling json2: %v", err)
+	}
+	c.walk("", value1, value2, fn)
+	return nil
+}
+
+// walk visits location and its children, and returns false if fn stopped the walk.
+func (c *Comparer) walk(location string, value1, value2 interface{}, fn func(path string, v1, v2 interface{}) WalkAction) bool {
+	rule := c.ruleFor(location)
+	if rule.ignore {
+		return true
+	}
+	switch fn(location, value1, value2) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+	map1, _ := value1.(map[string]interface{})
+	map2, _ := value2.(map[string]interface{})
+	for _, key := range mergedKeys(map1, map2) {
+		if !c.walk(getLocation(location, key), map1[key], map2[key], fn) {
+			return false
+		}
+	}
+	if pairs, ok := keyedPairs(value1, value2, rule.arrayKey); ok {
+		for _, pair := range pairs {
+			if !c.walk(location+pair.index, pair.value1, pair.value2, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	slice1, _ := value1.([]interface{})
+	slice2, _ := value2.([]interface{})
+	for i := 0; i < len(slice1) || i < len(slice2); i++ {
+		if !c.walk(fmt.Sprintf("%s[%d]", location, i), element(slice1, i), element(slice2, i), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// element returns the element of slice at i, or nil if there isn't one.
+func element(slice []interface{}, i int) interface{} {
+	if i < len(slice) {
+		return slice[i]
+	}
+	return nil
+}
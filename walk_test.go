@@ -0,0 +1,115 @@
+package jsonassert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name     string
+		comparer *Comparer
+		json1    string
+		json2    string
+		actions  map[string]WalkAction
+		visits   []string
+		err      error
+	}{
+		{
+			name:  "paired values",
+			json1: `{"b": [1, 2], "a": "x"}`,
+			json2: `{"a": "y", "b": [1], "c": null}`,
+			visits: []string{
+				": map[a:x b:[1 2]] vs. map[a:y b:[1] c:<nil>]",
+				"a: x vs. y",
+				"b: [1 2] vs. [1]",
+				"b[0]: 1 vs. 1",
+				"b[1]: 2 vs. <nil>",
+				"c: <nil> vs. <nil>",
+			},
+		},
+		{
+			name:  "mismatched types",
+			json1: `{"a": {"b": 1}}`,
+			json2: `{"a": [2]}`,
+			visits: []string{
+				": map[a:map[b:1]] vs. map[a:[2]]",
+				"a: map[b:1] vs. [2]",
+				"a.b: 1 vs. <nil>",
+				"a[0]: <nil> vs. 2",
+			},
+		},
+		{
+			name:    "skip children",
+			json1:   `{"a": {"b": 1}, "c": 2}`,
+			json2:   `{"a": {"b": 1}, "c": 3}`,
+			actions: map[string]WalkAction{"a": WalkSkipChildren},
+			visits: []string{
+				": map[a:map[b:1] c:2] vs. map[a:map[b:1] c:3]",
+				"a: map[b:1] vs. map[b:1]",
+				"c: 2 vs. 3",
+			},
+		},
+		{
+			name:    "stop",
+			json1:   `[1, 2, 3]`,
+			json2:   `[1, 2, 3]`,
+			actions: map[string]WalkAction{"[1]": WalkStop},
+			visits: []string{
+				": [1 2 3] vs. [1 2 3]",
+				"[0]: 1 vs. 1",
+				"[1]: 2 vs. 2",
+			},
+		},
+		{
+			name:     "ignored paths",
+			comparer: NewComparer(WithIgnorePaths("meta")),
+			json1:    `{"id": 1, "meta": {"trace": "x"}}`,
+			json2:    `{"id": 1}`,
+			visits: []string{
+				": map[id:1 meta:map[trace:x]] vs. map[id:1]",
+				"id: 1 vs. 1",
+			},
+		},
+		{
+			name:     "array keys",
+			comparer: NewComparer(WithArrayKey("items", "id")),
+			json1:    `{"items": [{"id": 1}, {"id": 2}]}`,
+			json2:    `{"items": [{"id": 2}]}`,
+			visits: []string{
+				": map[items:[map[id:1] map[id:2]]] vs. map[items:[map[id:2]]]",
+				"items: [map[id:1] map[id:2]] vs. [map[id:2]]",
+				"items[0]: map[id:1] vs. <nil>",
+				"items[0].id: 1 vs. <nil>",
+				"items[1]: map[id:2] vs. map[id:2]",
+				"items[1].id: 2 vs. 2",
+			},
+		},
+		{
+			name:  "invalid json",
+			json1: `{}`,
+			json2: `{`,
+			err:   fmt.Errorf("error unmarshalling json2: unexpected end of JSON input"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.comparer
+			if c == nil {
+				c = NewComparer()
+			}
+			var visits []string
+			err := c.Walk([]byte(tt.json1), []byte(tt.json2), func(path string, v1, v2 interface{}) WalkAction {
+				visits = append(visits, fmt.Sprintf("%s: %v vs. %v", path, v1, v2))
+				return tt.actions[path]
+			})
+			if fmt.Sprint(err) != fmt.Sprint(tt.err) {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(visits, tt.visits) {
+				t.Errorf("visits = %q, want %q", visits, tt.visits)
+			}
+		})
+	}
+}
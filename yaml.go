@@ -0,0 +1,343 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a line of a YAML document with its comment and indentation removed.
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+}
+
+// parseYAML parses the subset of YAML used by config files into the same values
+// json.Unmarshal produces for an interface{}: block and flow mappings and sequences, and plain,
+// single-quoted and double-quoted scalars. Anchors, tags, multiple documents and block scalars
+// are not supported.
+func parseYAML(text []byte) (interface{}, error) {
+	var lines []yamlLine
+	for i, line := range strings.Split(string(text), "\n") {
+		line = strings.TrimRight(stripYAMLComment(line), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", i+1)
+		}
+		lines = append(lines, yamlLine{number: i + 1, indent: len(line) - len(content), content: content})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	p := &yamlParser{lines: lines}
+	value, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].number)
+	}
+	return value, nil
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	line := p.lines[p.pos]
+	if line.content == "-" || strings.HasPrefix(line.content, "- ") {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitYAMLKey(line.content); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return parseYAMLFlow(line.content, line.number)
+}
+
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	sequence := []interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if line.content != "-" && !strings.HasPrefix(line.content, "- ") {
+			break
+		}
+		item := strings.TrimLeft(strings.TrimPrefix(line.content, "-"), " ")
+		if item == "" {
+			p.pos++
+			value, err := p.parseNested(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+		// the item starts on the same line as the dash, so treat it as if it were indented
+		// on its own line
+		itemIndent := line.indent + len(line.content) - len(item)
+		p.lines[p.pos] = yamlLine{number: line.number, indent: itemIndent, content: item}
+		value, err := p.parseBlock(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+	}
+	return sequence, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	mapping := map[string]interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		key, rest, ok := splitYAMLKey(line.content)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key", line.number)
+		}
+		if _, ok := mapping[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+		}
+		p.pos++
+		if rest != "" {
+			value, err := parseYAMLFlow(rest, line.number)
+			if err != nil {
+				return nil, err
+			}
+			mapping[key] = value
+			continue
+		}
+		value, err := p.parseNested(indent, true)
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+	}
+	return mapping, nil
+}
+
+// parseNested parses the block following a key or dash with nothing after it. The block must be
+// indented further, except that the sequence following a key may be at the same indentation as
+// the key.
+func (p *yamlParser) parseNested(indent int, afterKey bool) (interface{}, error) {
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	isSequence := next.content == "-" || strings.HasPrefix(next.content, "- ")
+	if next.indent > indent || next.indent == indent && isSequence && afterKey {
+		return p.parseBlock(next.indent)
+	}
+	return nil, nil
+}
+
+// splitYAMLKey splits a "key: value" line. The key may be quoted.
+func splitYAMLKey(content string) (string, string, bool) {
+	if strings.HasPrefix(content, `"`) || strings.HasPrefix(content, "'") {
+		key, n, err := parseYAMLQuoted(content)
+		if err != nil || !strings.HasPrefix(content[n:], ":") {
+			return "", "", false
+		}
+		rest := content[n+1:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest), true
+	}
+	if strings.HasPrefix(content, "[") || strings.HasPrefix(content, "{") {
+		return "", "", false
+	}
+	i := strings.Index(content, ": ")
+	if i == -1 {
+		if !strings.HasSuffix(content, ":") {
+			return "", "", false
+		}
+		i = len(content) - 1
+	}
+	return strings.TrimSpace(content[:i]), strings.TrimSpace(content[i+1:]), true
+}
+
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseYAMLFlow parses a value written on a single line: a scalar, or a flow sequence or
+// mapping such as [a, b] or {tolerance: 0.01}.
+func parseYAMLFlow(s string, number int) (interface{}, error) {
+	if strings.HasPrefix(s, "|") || strings.HasPrefix(s, ">") || strings.HasPrefix(s, "&") || strings.HasPrefix(s, "*") || strings.HasPrefix(s, "!") {
+		return nil, fmt.Errorf("line %d: unsupported YAML syntax %q", number, s)
+	}
+	if !strings.ContainsAny(s[:1], `[{"'`) {
+		value, err := yamlPlainScalar(s)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", number, err)
+		}
+		return value, nil
+	}
+	f := &yamlFlow{s: s}
+	value, err := f.parseValue()
+	if err == nil {
+		f.skipSpace()
+		if f.pos < len(f.s) {
+			err = fmt.Errorf("unexpected %q", f.s[f.pos:])
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %v", number, err)
+	}
+	return value, nil
+}
+
+type yamlFlow struct {
+	s   string
+	pos int
+}
+
+func (f *yamlFlow) skipSpace() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) parseValue() (interface{}, error) {
+	f.skipSpace()
+	if f.pos >= len(f.s) {
+		return nil, nil
+	}
+	switch f.s[f.pos] {
+	case '[':
+		f.pos++
+		sequence := []interface{}{}
+		err := f.parseItems(']', func() error {
+			value, err := f.parseValue()
+			sequence = append(sequence, value)
+			return err
+		})
+		return sequence, err
+	case '{':
+		f.pos++
+		mapping := map[string]interface{}{}
+		err := f.parseItems('}', func() error {
+			key, err := f.parseScalar(":,}")
+			if err != nil {
+				return err
+			}
+			if f.pos >= len(f.s) || f.s[f.pos] != ':' {
+				return fmt.Errorf("expected ':' after key %v", key)
+			}
+			f.pos++
+			value, err := f.parseValue()
+			mapping[fmt.Sprint(key)] = value
+			return err
+		})
+		return mapping, err
+	}
+	return f.parseScalar(",]}")
+}
+
+func (f *yamlFlow) parseItems(end byte, parseItem func() error) error {
+	for {
+		f.skipSpace()
+		if f.pos >= len(f.s) {
+			return fmt.Errorf("missing %q", end)
+		}
+		if f.s[f.pos] == end {
+			f.pos++
+			return nil
+		}
+		if err := parseItem(); err != nil {
+			return err
+		}
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == ',' {
+			f.pos++
+		}
+	}
+}
+
+func (f *yamlFlow) parseScalar(stop string) (interface{}, error) {
+	f.skipSpace()
+	if f.pos < len(f.s) && (f.s[f.pos] == '"' || f.s[f.pos] == '\'') {
+		value, n, err := parseYAMLQuoted(f.s[f.pos:])
+		f.pos += n
+		return value, err
+	}
+	start := f.pos
+	for f.pos < len(f.s) && !strings.ContainsRune(stop, rune(f.s[f.pos])) {
+		f.pos++
+	}
+	return yamlPlainScalar(strings.TrimSpace(f.s[start:f.pos]))
+}
+
+// parseYAMLQuoted parses the quoted string at the start of s and returns it along with the
+// number of bytes it used.
+func parseYAMLQuoted(s string) (string, int, error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case quote == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(s[1:i], "''", "'"), i + 1, nil
+			}
+			var value string
+			if err := json.Unmarshal([]byte(s[:i+1]), &value); err != nil {
+				return "", i + 1, fmt.Errorf("invalid string %s", s[:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", len(s), fmt.Errorf("unterminated string %s", s)
+}
+
+// yamlNumber matches the decimal numbers of the YAML core schema.
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// yamlPlainScalar returns the value of an unquoted scalar. Infinities and NaN, in either the YAML
+// or the strconv spelling, are reported as errors, since JSON can't represent them.
+func yamlPlainScalar(s string) (interface{}, error) {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if yamlNumber.MatchString(s) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s is out of range", s)
+		}
+		return f, nil
+	}
+	switch strings.ToLower(strings.TrimLeft(s, "+-")) {
+	case "inf", ".inf", "infinity", "nan", ".nan":
+		return nil, fmt.Errorf("unsupported number %q: must be finite", s)
+	}
+	return s, nil
+}
@@ -0,0 +1,62 @@
+package jsonassert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{"scalars", "a: 1\nb: text # comment\nc: true\nd: null\ne: 'it''s'\nf: \"tab\\t\"\ng: ~\nh: a#b", `{"a":1,"b":"text","c":true,"d":null,"e":"it's","f":"tab\t","g":null,"h":"a#b"}`},
+		{"nested mapping", "a:\n  b:\n    c: 1\n  d: 2\ne: 3", `{"a":{"b":{"c":1},"d":2},"e":3}`},
+		{"sequence", "a:\n  - 1\n  - x\nb:\n- y", `{"a":[1,"x"],"b":["y"]}`},
+		{"sequence of mappings", "- a: 1\n  b: 2\n- a: 3", `[{"a":1,"b":2},{"a":3}]`},
+		{"nested sequences", "-\n  - 1\n- - 2\n  - 3", `[[1],[2,3]]`},
+		{"flow", `a: [1, "x,y", {b: 2, "c d": [true]}]`, `{"a":[1,"x,y",{"b":2,"c d":[true]}]}`},
+		{"regex scalar", `a: ^[a-z]{3}$`, `{"a":"^[a-z]{3}$"}`},
+		{"document marker", "---\na: 1", `{"a":1}`},
+		{"numbers", "a: [.5, +1, 1., 2e3, 1_000, 0x10, Infinity2]", `{"a":[0.5,1,1,2000,"1_000","0x10","Infinity2"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parseYAML([]byte(tt.yaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, _ := json.Marshal(value)
+			if string(actual) != tt.expected {
+				t.Errorf("yaml mismatch. want: %s, got: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{"duplicate key", "a: 1\na: 2", `line 2: duplicate key "a"`},
+		{"bad indentation", "a:\n    b: 1\n  c: 2", "line 3: unexpected indentation"},
+		{"block scalar", "a: |\n  text", `line 1: unsupported YAML syntax "|"`},
+		{"unterminated flow", "a: [1, 2", `line 1: missing ']'`},
+		{"multi-line flow mapping", "a: {b: 1,\n  c: 2}", `line 1: missing '}'`},
+		{"infinity", "a: 1\nb: inf", `line 2: unsupported number "inf": must be finite`},
+		{"yaml infinity", "a: -.inf", `line 1: unsupported number "-.inf": must be finite`},
+		{"nan", "a: [1, NaN]", `line 1: unsupported number "NaN": must be finite`},
+		{"out of range", "a: 1e999", "line 1: number 1e999 is out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseYAML([]byte(tt.yaml))
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("error mismatch. want: %s, got: %v", tt.expected, err)
+			}
+		})
+	}
+}